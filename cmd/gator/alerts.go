@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/alerts"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/google/uuid"
+)
+
+func handlerAlerts(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return listAlerts(s, cmd, user)
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "list":
+		if len(args) != 0 {
+			return cmd.usageError("'alerts list' expects no arguments")
+		}
+		return listAlerts(s, cmd, user)
+	case "watch":
+		if len(args) != 1 {
+			return cmd.usageError("'alerts watch' expects 1 argument (word)")
+		}
+		word := strings.TrimSpace(args[0])
+		if word == "" {
+			return cmd.usageError("the watch word can't be empty")
+		}
+		params := database.CreateWatchWordParams{
+			ID:             uuid.New(),
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+			UserID:         user.ID,
+			Word:           word,
+			Regex:          cmd.boolFlag("regex"),
+			NotifyWebhooks: cmd.boolFlag("webhook"),
+			NotifyEmail:    cmd.boolFlag("email"),
+		}
+		if _, err := alerts.Compile(database.WatchWord(params)); err != nil {
+			return cmd.usageError("%v", err)
+		}
+		if params.NotifyEmail && (s.config.Smtp == nil || s.config.Smtp.Host == "" || s.config.Smtp.From == "") {
+			return fmt.Errorf("no mail server set, add smtp with a host and a from address to the config file")
+		}
+		if params.NotifyEmail && user.Email == "" {
+			return fmt.Errorf("%s has no email address, set one with 'gator alerts email <address>'", user.Name)
+		}
+		w, err := s.db.CreateWatchWord(context.Background(), params)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Watching for '%s' in new posts%s\n", w.Word, notifiers(w))
+	case "unwatch":
+		if len(args) != 1 {
+			return cmd.usageError("'alerts unwatch' expects 1 argument (word)")
+		}
+		removed, err := s.db.DeleteWatchWord(context.Background(), database.DeleteWatchWordParams{
+			UserID: user.ID,
+			Word:   args[0],
+		})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("you aren't watching for '%s'", args[0])
+		}
+		fmt.Printf("No longer watching for '%s', its alerts have been removed\n", args[0])
+	case "words":
+		if len(args) != 0 {
+			return cmd.usageError("'alerts words' expects no arguments")
+		}
+		return listWatchWords(s, user)
+	case "clear":
+		if len(args) != 0 {
+			return cmd.usageError("'alerts clear' expects no arguments")
+		}
+		cleared, err := s.db.DeleteAlertsForUser(context.Background(), user.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cleared %d alerts\n", cleared)
+	case "email":
+		if len(args) > 1 {
+			return cmd.usageError("'alerts email' expects at most 1 argument (address or off)")
+		}
+		return setAlertEmail(s, user, args)
+	default:
+		return cmd.usageError("unknown alerts subcommand '%s', expected one of: list, watch, unwatch, words, clear, email", cmd.args[0])
+	}
+	return nil
+}
+
+func listAlerts(s *state, cmd command, user database.User) error {
+	limit := cmd.intFlag("limit")
+	if limit < 1 {
+		return cmd.usageError("limit must be a positive number")
+	}
+	found, err := s.db.GetAlertsForUser(context.Background(), database.GetAlertsForUserParams{
+		UserID: user.ID,
+		Word:   optionalString(cmd.stringFlag("word")),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	l := output.NewList("id", "word", "post_id", "title", "url", "feed_name", "snippet", "created_at")
+	for _, a := range found {
+		l.Add(a.ID, a.Word, a.PostID, a.PostTitle, a.PostUrl, a.FeedName, a.Snippet, a.CreatedAt)
+	}
+
+	return s.render(l, func() {
+		if len(found) == 0 {
+			fmt.Println("No alerts, watch for words in new posts with 'gator alerts watch <word>'")
+			return
+		}
+		for _, a := range found {
+			fmt.Printf("%s '%s' in %s\n", a.CreatedAt.Format(time.DateTime), a.Word, htmltext.Clean(a.FeedName))
+			fmt.Println("Title:", htmltext.Clean(a.PostTitle))
+			fmt.Println("URL:", htmltext.Clean(a.PostUrl))
+			fmt.Println(htmltext.Clean(a.Snippet))
+			fmt.Println()
+		}
+	})
+}
+
+func listWatchWords(s *state, user database.User) error {
+	words, err := s.db.GetWatchWordsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	l := output.NewList("word", "regex", "webhooks", "email", "created_at")
+	for _, w := range words {
+		l.Add(w.Word, w.Regex, w.NotifyWebhooks, w.NotifyEmail, w.CreatedAt)
+	}
+
+	return s.render(l, func() {
+		if len(words) == 0 {
+			fmt.Println("No watch words yet, add one with 'gator alerts watch <word>'")
+		}
+		for _, w := range words {
+			kind := "word"
+			if w.Regex {
+				kind = "regex"
+			}
+			fmt.Printf("%s (%s)%s\n", w.Word, kind, notifiers(w))
+		}
+	})
+}
+
+// setAlertEmail shows the address the user's alerts are emailed to, or sets
+// it to args[0], or removes it if args[0] is "off".
+func setAlertEmail(s *state, user database.User, args []string) error {
+	if len(args) == 0 {
+		if user.Email == "" {
+			fmt.Printf("%s has no email address, set one with 'gator alerts email <address>'\n", user.Name)
+			return nil
+		}
+		fmt.Printf("Alerts of %s are emailed to %s\n", user.Name, user.Email)
+		return nil
+	}
+
+	email := ""
+	if args[0] != "off" {
+		addr, err := mail.ParseAddress(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid email address '%s'", args[0])
+		}
+		email = addr.Address
+	}
+	err := s.db.SetUserEmail(context.Background(), database.SetUserEmailParams{
+		ID:        user.ID,
+		Email:     email,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if email == "" {
+		fmt.Printf("Removed the email address of %s, alerts are no longer emailed\n", user.Name)
+		return nil
+	}
+	fmt.Printf("Alerts of %s are now emailed to %s\n", user.Name, email)
+	return nil
+}
+
+// notifiers describes where the alerts of a watch word are sent besides
+// 'gator alerts'.
+func notifiers(w database.WatchWord) string {
+	sent := []string{}
+	if w.NotifyWebhooks {
+		sent = append(sent, "webhooks")
+	}
+	if w.NotifyEmail {
+		sent = append(sent, "email")
+	}
+	if len(sent) == 0 {
+		return ""
+	}
+	return ", sent by " + strings.Join(sent, " and ")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/archive"
+	"github.com/R0Xps/gatorcli/internal/config"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/google/uuid"
+)
+
+const archivePageSize = 100
+
+func handlerArchive(s *state, cmd command, user database.User) error {
+	starred := cmd.boolFlag("starred")
+	if starred == (len(cmd.args) == 1) {
+		return cmd.usageError("expected either a post ID or --starred")
+	}
+	format, err := archive.ParseFormat(cmd.stringFlag("format"))
+	if err != nil {
+		return cmd.usageError("%v", err)
+	}
+	dir := cmd.stringFlag("dir")
+	if dir == "" {
+		dir, err = archiveDir(s.config)
+		if err != nil {
+			return err
+		}
+	}
+	dir, err = filepath.Abs(filepath.Join(dir, user.Name))
+	if err != nil {
+		return err
+	}
+	images := !cmd.boolFlag("no-images")
+
+	feedNames, err := followedFeedNames(s, user)
+	if err != nil {
+		return err
+	}
+
+	if !starred {
+		post, err := getPost(s, cmd.args[0])
+		if err != nil {
+			return err
+		}
+		path, err := archivePost(s, user, post, feedNames[post.FeedID], format, dir, images)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Archived '%s' to %s\n", htmltext.Clean(post.Title), path)
+		return nil
+	}
+
+	archived, skipped := 0, 0
+	before := uuid.NullUUID{}
+	for {
+		posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID:      user.ID,
+			Sort:        "published",
+			StarredOnly: true,
+			Before:      before,
+			Limit:       archivePageSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, row := range posts {
+			_, err := s.db.GetPostArchive(context.Background(), database.GetPostArchiveParams{
+				UserID: user.ID,
+				PostID: row.ID,
+			})
+			if err == nil {
+				skipped++
+				continue
+			}
+			if err != sql.ErrNoRows {
+				return err
+			}
+			post, err := s.db.GetPost(context.Background(), row.ID)
+			if err != nil {
+				return err
+			}
+			path, err := archivePost(s, user, post, feedNames[post.FeedID], format, dir, images)
+			if err != nil {
+				return fmt.Errorf("couldn't archive '%s': %w", post.Title, err)
+			}
+			fmt.Printf("Archived '%s' to %s\n", htmltext.Clean(post.Title), path)
+			archived++
+		}
+		if len(posts) < archivePageSize {
+			break
+		}
+		before = uuid.NullUUID{UUID: posts[len(posts)-1].ID, Valid: true}
+	}
+	fmt.Printf("Archived %d starred posts, skipped %d already archived\n", archived, skipped)
+	return nil
+}
+
+// archivePost saves a local copy of a post in dir and records it, returning
+// the path of the copy. The post's full text is used if it was extracted,
+// otherwise it's extracted now, falling back to the description from the
+// feed if that fails.
+func archivePost(s *state, user database.User, post database.Post, feedName string, format archive.Format, dir string, images bool) (string, error) {
+	content := post.Content
+	if content == "" {
+		fullText, err := fetchFullText(context.Background(), post.Url)
+		if err == nil {
+			content = fullText
+		} else {
+			content = post.Description
+		}
+	}
+
+	article := archive.Article{
+		Title:     post.Title,
+		URL:       post.Url,
+		Feed:      feedName,
+		Published: post.PublishedAt,
+		Content:   content,
+	}
+	path := filepath.Join(dir, archive.FileName(article, post.ID.String()[:8], format))
+	if err := archive.Save(context.Background(), article, format, path, images); err != nil {
+		return "", err
+	}
+
+	_, err := s.db.SavePostArchive(context.Background(), database.SavePostArchiveParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+		Format:    string(format),
+		Path:      path,
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// archiveDir is the directory posts are archived in: archive_dir from the
+// config file, or gator-archive in the home directory.
+func archiveDir(c *config.Config) (string, error) {
+	if c.Archive_dir != "" {
+		return c.Archive_dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "gator-archive"), nil
+}
+
+func followedFeedNames(s *state, user database.User) (map[uuid.UUID]string, error) {
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return nil, err
+	}
+	names := map[uuid.UUID]string{}
+	for _, feedFollow := range feedFollows {
+		names[feedFollow.FeedID] = feedFollow.FeedName
+	}
+	return names, nil
+}
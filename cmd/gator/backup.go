@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/R0Xps/gatorcli/internal/backup"
+)
+
+func handlerBackup(s *state, cmd command) error {
+	// Backups hold secrets, like webhook secrets and Fever API keys, so only
+	// the user can read them, even when overwriting an existing file.
+	f, err := os.OpenFile(cmd.args[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+
+	// A read-only snapshot keeps the archive consistent while the aggregator
+	// keeps adding posts.
+	ctx := context.Background()
+	tx, err := s.conn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stats, err := backup.Write(ctx, s.db.WithTx(tx), f)
+	if err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up %s to %s\n", formatBackupStats(stats), cmd.args[0])
+	return nil
+}
+
+func handlerRestore(s *state, cmd command) error {
+	f, err := os.Open(cmd.args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stats, err := backup.Restore(ctx, s.db.WithTx(tx), f)
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored %s from %s\n", formatBackupStats(stats), cmd.args[0])
+	return nil
+}
+
+func formatBackupStats(stats backup.Stats) string {
+	types := []string{}
+	for recordType := range stats {
+		types = append(types, recordType)
+	}
+	slices.Sort(types)
+
+	counts := []string{}
+	for _, recordType := range types {
+		counts = append(counts, fmt.Sprintf("%d %s records", stats[recordType], recordType))
+	}
+	if len(counts) == 0 {
+		return "no records"
+	}
+	return strings.Join(counts, ", ")
+}
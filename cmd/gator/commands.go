@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type command struct {
+	name  string
+	args  []string
+	flags *flag.FlagSet
+}
+
+// commandInfo describes how a command is called, for parsing its arguments
+// and for the help output.
+type commandInfo struct {
+	// args is the usage of the positional arguments, e.g. "<feed_url> [limit]".
+	args        string
+	description string
+	minArgs     int
+	// maxArgs is the most positional arguments the command accepts, or -1 if
+	// there is no limit.
+	maxArgs int
+	// flags declares the command's flags, which handlers read back with
+	// command.stringFlag, command.intFlag and command.boolFlag.
+	flags func(fs *flag.FlagSet)
+	// complete lists the values the next positional argument can take, given
+	// the positional arguments before it, for shell completion.
+	complete func(s *state, args []string) []string
+	// completeFlags lists the values each flag can take, by flag name.
+	completeFlags map[string]func(s *state, args []string) []string
+	// rawArgs passes all arguments to the handler as they are, without
+	// parsing them as flags.
+	rawArgs bool
+	// offline commands don't use the database, so it isn't connected to
+	// before they run.
+	offline bool
+	hidden  bool
+}
+
+type commands struct {
+	commands map[string]func(*state, command) error
+	info     map[string]commandInfo
+	// names keeps the commands in the order they were registered in, which is
+	// the order they're listed in by help.
+	names []string
+}
+
+// usageError is returned when a command is called with invalid arguments, so
+// that its usage can be shown alongside the error.
+type usageError struct {
+	command string
+	err     error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (c *commands) run(s *state, cmd command) error {
+	if cmd.name == "help" || cmd.name == "--help" || cmd.name == "-h" {
+		if len(cmd.args) == 0 {
+			c.printHelp(os.Stdout)
+			return nil
+		}
+		return c.printCommandHelp(os.Stdout, cmd.args[0])
+	}
+
+	commandHandler, ok := c.commands[cmd.name]
+	if !ok {
+		return usageError{err: fmt.Errorf("command '%s' not found", cmd.name)}
+	}
+	info := c.info[cmd.name]
+
+	cmd.flags = c.flagSet(cmd.name)
+	if !info.rawArgs {
+		args, err := parseFlags(cmd.flags, cmd.args)
+		if err == flag.ErrHelp {
+			return c.printCommandHelp(os.Stdout, cmd.name)
+		}
+		if err != nil {
+			return usageError{command: cmd.name, err: err}
+		}
+		cmd.args = args
+	}
+
+	if len(cmd.args) < info.minArgs {
+		return cmd.usageError("missing arguments")
+	}
+	if info.maxArgs >= 0 && len(cmd.args) > info.maxArgs {
+		return cmd.usageError("too many arguments")
+	}
+
+	if !info.offline {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+	return commandHandler(s, cmd)
+}
+
+func (c *commands) register(name string, f func(*state, command) error, info commandInfo) {
+	c.commands[name] = f
+	c.info[name] = info
+	c.names = append(c.names, name)
+}
+
+// flagSet creates a new flag set with the flags of the named command. It
+// doesn't print anything by itself, errors are reported by run's caller.
+func (c *commands) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	if flags := c.info[name].flags; flags != nil {
+		flags(fs)
+	}
+	return fs
+}
+
+func (c *commands) printHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gator [--output <format>] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range c.names {
+		info := c.info[name]
+		if info.hidden {
+			continue
+		}
+		summary, _, _ := strings.Cut(info.description, "\n")
+		fmt.Fprintf(tw, "  %s\t%s\n", name, summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	fmt.Fprintln(w, "  -o, --output <format>  print results as text (default), json, csv, tsv or table")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'gator help <command>' for more information about a command.")
+}
+
+func (c *commands) printCommandHelp(w io.Writer, name string) error {
+	info, ok := c.info[name]
+	if !ok {
+		return usageError{err: fmt.Errorf("command '%s' not found", name)}
+	}
+
+	fmt.Fprintln(w, "Usage:", c.usage(name))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, info.description)
+
+	fs := c.flagSet(name)
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		buf := bytes.Buffer{}
+		fs.SetOutput(&buf)
+		fs.PrintDefaults()
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Flags:")
+		fmt.Fprint(w, buf.String())
+	}
+	return nil
+}
+
+func (c *commands) usage(name string) string {
+	info := c.info[name]
+	usage := "gator " + name
+	if info.flags != nil {
+		usage += " [flags]"
+	}
+	if info.args != "" {
+		usage += " " + info.args
+	}
+	return usage
+}
+
+func (cmd command) usageError(format string, a ...any) error {
+	return usageError{command: cmd.name, err: fmt.Errorf(format, a...)}
+}
+
+func (cmd command) stringFlag(name string) string {
+	return cmd.flags.Lookup(name).Value.String()
+}
+
+func (cmd command) intFlag(name string) int {
+	return cmd.flags.Lookup(name).Value.(flag.Getter).Get().(int)
+}
+
+func (cmd command) boolFlag(name string) bool {
+	return cmd.flags.Lookup(name).Value.(flag.Getter).Get().(bool)
+}
+
+// parseFlags parses the flags in args, allowing them to be mixed with
+// positional arguments, and returns the positional arguments in order.
+// Everything after a "--" argument is positional.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		// Parse stops at the first positional argument, or right after "--".
+		rest := fs.Args()
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	return positional, nil
+}
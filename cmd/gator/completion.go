@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/output"
+)
+
+const bashCompletion = `# bash completion for gator
+_gator_completions() {
+    local cur words cword
+    if declare -F _get_comp_words_by_ref >/dev/null 2>&1; then
+        _get_comp_words_by_ref -n =: cur words cword
+    else
+        cur="${COMP_WORDS[COMP_CWORD]}"
+        words=("${COMP_WORDS[@]}")
+        cword=$COMP_CWORD
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($(gator __complete "${words[@]:1:cword}" 2>/dev/null))
+    if declare -F __ltrim_colon_completions >/dev/null 2>&1; then
+        __ltrim_colon_completions "$cur"
+    fi
+}
+complete -o default -F _gator_completions gator
+`
+
+const zshCompletion = `#compdef gator
+# zsh completion for gator
+_gator() {
+    local -a completions
+    completions=(${(f)"$(gator __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+    compadd -- "${completions[@]}"
+}
+compdef _gator gator
+`
+
+const fishCompletion = `# fish completion for gator
+function __gator_complete
+    set -l args (commandline -opc)[2..-1] (commandline -ct)
+    gator __complete $args 2>/dev/null
+end
+complete -c gator -f -a '(__gator_complete)'
+`
+
+func handlerCompletion(s *state, cmd command) error {
+	switch cmd.args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		return cmd.usageError("unknown shell '%s', expected one of: bash, zsh, fish", cmd.args[0])
+	}
+	return nil
+}
+
+// handlerComplete prints the possible values of the last word of a command
+// line, one per line. The words before it decide what is being completed: a
+// command name, a flag, a flag's value or a positional argument.
+func (c *commands) handlerComplete(s *state, cmd command) error {
+	if len(cmd.args) == 0 {
+		cmd.args = []string{""}
+	}
+	words, current := cmd.args[:len(cmd.args)-1], cmd.args[len(cmd.args)-1]
+
+	candidates := c.completions(s, words, current)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) {
+			fmt.Println(candidate)
+		}
+	}
+	return nil
+}
+
+func (c *commands) completions(s *state, words []string, current string) []string {
+	// Global flags can appear anywhere before "--", so they're removed first.
+	rest := []string{}
+	for i := 0; i < len(words); i++ {
+		if words[i] == "--" {
+			rest = append(rest, words[i:]...)
+			break
+		}
+		if words[i] == "--output" || words[i] == "-o" {
+			if i+1 == len(words) {
+				return formatNames()
+			}
+			i++
+			continue
+		}
+		if !strings.HasPrefix(words[i], "--output=") && !strings.HasPrefix(words[i], "-o=") {
+			rest = append(rest, words[i])
+		}
+	}
+	words = rest
+
+	if len(words) == 0 {
+		if strings.HasPrefix(current, "-") {
+			return []string{"--output", "--help"}
+		}
+		names := []string{"help"}
+		for _, name := range c.names {
+			if !c.info[name].hidden {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	name := words[0]
+	if name == "help" {
+		if len(words) == 1 {
+			return c.completions(s, nil, current)
+		}
+		return nil
+	}
+	info, ok := c.info[name]
+	if !ok || info.rawArgs {
+		return nil
+	}
+
+	fs := c.flagSet(name)
+	positional := []string{}
+	for i := 1; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			positional = append(positional, words[i+1:]...)
+			return completeWith(s, info.complete, positional)
+		}
+		if len(word) < 2 || word[0] != '-' || strings.Contains(word, "=") {
+			positional = append(positional, word)
+			continue
+		}
+		f := fs.Lookup(strings.TrimLeft(word, "-"))
+		if f == nil || isBoolFlag(f) {
+			continue
+		}
+		if i+1 == len(words) {
+			return completeWith(s, info.completeFlags[f.Name], positional)
+		}
+		i++
+	}
+
+	if strings.HasPrefix(current, "-") {
+		flags := []string{"--help"}
+		fs.VisitAll(func(f *flag.Flag) {
+			flags = append(flags, "--"+f.Name)
+		})
+		return flags
+	}
+	return completeWith(s, info.complete, positional)
+}
+
+func completeWith(s *state, complete func(*state, []string) []string, args []string) []string {
+	if complete == nil {
+		return nil
+	}
+	return complete(s, args)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func formatNames() []string {
+	names := []string{}
+	for _, format := range output.Formats {
+		names = append(names, string(format))
+	}
+	return names
+}
+
+func completeWords(words ...string) func(*state, []string) []string {
+	return func(*state, []string) []string {
+		return words
+	}
+}
+
+// completionUser returns the currently logged in user, for completing the
+// things they own. Completion is best effort, so errors are only reported
+// by returning false.
+func completionUser(s *state) (database.User, bool) {
+	if err := s.connect(); err != nil || s.config.Current_user_name == "" {
+		return database.User{}, false
+	}
+	user, err := s.db.GetUser(context.Background(), s.config.Current_user_name)
+	return user, err == nil
+}
+
+func completeUsers(s *state, _ []string) []string {
+	if err := s.connect(); err != nil {
+		return nil
+	}
+	users, err := s.db.GetUsers(context.Background())
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, user := range users {
+		names = append(names, user.Name)
+	}
+	return names
+}
+
+func completeFeeds(s *state, _ []string) []string {
+	if err := s.connect(); err != nil {
+		return nil
+	}
+	feeds, err := s.db.GetFeeds(context.Background())
+	if err != nil {
+		return nil
+	}
+	urls := []string{}
+	for _, feed := range feeds {
+		urls = append(urls, feed.Url)
+	}
+	return urls
+}
+
+func completeFollowedFeeds(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return nil
+	}
+	urls := []string{}
+	for _, feedFollow := range feedFollows {
+		urls = append(urls, feedFollow.FeedUrl)
+	}
+	return urls
+}
+
+func completeFolders(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	folders, err := s.db.GetFoldersForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, folder := range folders {
+		names = append(names, folder.Name)
+	}
+	return names
+}
+
+func completeWebhooks(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	webhooks, err := s.db.GetWebhooksForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil
+	}
+	ids := []string{}
+	for _, w := range webhooks {
+		ids = append(ids, w.ID.String())
+	}
+	return ids
+}
+
+func completeRules(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	saved, err := s.db.GetRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil
+	}
+	ids := []string{}
+	for _, r := range saved {
+		ids = append(ids, r.ID.String())
+	}
+	return ids
+}
+
+func completeWatchWords(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	words, err := s.db.GetWatchWordsForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, w := range words {
+		names = append(names, w.Word)
+	}
+	return names
+}
+
+func completeTags(s *state, _ []string) []string {
+	user, ok := completionUser(s)
+	if !ok {
+		return nil
+	}
+	tags, err := s.db.GetTagsForUser(context.Background(), database.GetTagsForUserParams{
+		UserID: user.ID,
+	})
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// completePostTags completes the tags after the post ID of tag and untag.
+func completePostTags(s *state, args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return completeTags(s, args)
+}
+
+func completeFolderArgs(s *state, args []string) []string {
+	switch {
+	case len(args) == 0:
+		return []string{"list", "create", "rename", "delete", "move"}
+	case len(args) == 1 && (args[0] == "rename" || args[0] == "delete"):
+		return completeFolders(s, args)
+	case len(args) == 1 && args[0] == "move":
+		return completeFollowedFeeds(s, args)
+	case len(args) == 2 && args[0] == "move":
+		return completeFolders(s, args)
+	}
+	return nil
+}
+
+func completeWebhookArgs(s *state, args []string) []string {
+	switch {
+	case len(args) == 0:
+		return []string{"list", "add", "remove", "test", "log"}
+	case len(args) == 2 && args[0] == "add":
+		return completeFollowedFeeds(s, args)
+	case len(args) == 1 && (args[0] == "remove" || args[0] == "test" || args[0] == "log"):
+		return completeWebhooks(s, args)
+	}
+	return nil
+}
+
+// completeRuleArgs completes the subcommands of rule and the rule IDs they
+// take.
+func completeRuleArgs(s *state, args []string) []string {
+	switch {
+	case len(args) == 0:
+		return []string{"list", "add", "remove", "test", "apply", "unhide"}
+	case len(args) == 1 && (args[0] == "remove" || args[0] == "test" || args[0] == "apply"):
+		return completeRules(s, args)
+	}
+	return nil
+}
+
+func completeAlertsArgs(s *state, args []string) []string {
+	switch {
+	case len(args) == 0:
+		return []string{"list", "watch", "unwatch", "words", "clear", "email"}
+	case len(args) == 1 && args[0] == "unwatch":
+		return completeWatchWords(s, args)
+	case len(args) == 1 && args[0] == "email":
+		return []string{"off"}
+	}
+	return nil
+}
+
+// completeFormat completes the file format argument of import and export.
+func completeFormat(s *state, args []string) []string {
+	if len(args) == 0 {
+		return []string{"opml"}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/config"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/digest"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/google/uuid"
+)
+
+const (
+	digestPageSize = 100
+	// digestSummaryLength is the length of the descriptions shown in digests.
+	digestSummaryLength = 280
+)
+
+func handlerDigest(s *state, cmd command) error {
+	d, err := time.ParseDuration(cmd.stringFlag("since"))
+	if err != nil || d <= 0 {
+		return cmd.usageError("invalid duration '%s'", cmd.stringFlag("since"))
+	}
+	dryRun := cmd.boolFlag("dry-run")
+
+	userName := cmd.stringFlag("user")
+	if userName == "" {
+		userName = s.config.Current_user_name
+	}
+	user, err := s.db.GetUser(context.Background(), userName)
+	if err != nil {
+		return fmt.Errorf("user '%s' not found", userName)
+	}
+
+	smtpConfig := config.SMTP{}
+	if s.config.Smtp != nil {
+		smtpConfig = *s.config.Smtp
+	}
+	// Digests go to the user's own address, so that each user's digest reaches
+	// them, unless --to says otherwise.
+	to := cmp.Or(cmd.stringFlag("to"), user.Email, smtpConfig.To)
+	if !dryRun && (smtpConfig.Host == "" || smtpConfig.From == "") {
+		return fmt.Errorf("no mail server set, add smtp with a host and a from address to the config file")
+	}
+	if to == "" {
+		if !dryRun {
+			return fmt.Errorf("no address to send the digest to, set one with 'gator alerts email', use --to or add one to smtp in the config file")
+		}
+		to = "you@localhost"
+	}
+	from := smtpConfig.From
+	if from == "" {
+		from = "Gator <gator@localhost>"
+	}
+
+	dg, err := buildDigest(s, user, time.Now().Add(-d))
+	if err != nil {
+		return err
+	}
+	if dg.Count() == 0 && !dryRun {
+		fmt.Printf("No unread posts for %s since %s, not sending a digest\n", user.Name, dg.Since.Format(time.DateTime))
+		return nil
+	}
+
+	msg, err := digest.Message(dg, from, to)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		_, err := os.Stdout.Write(msg)
+		return err
+	}
+	if err := digest.Send(smtpConfig, to, msg); err != nil {
+		return fmt.Errorf("couldn't send the digest: %w", err)
+	}
+	fmt.Printf("Sent a digest of %d posts to %s\n", dg.Count(), to)
+	return nil
+}
+
+// buildDigest collects the posts user hasn't read that were fetched since the
+// given time, grouped by folder and then by feed, in alphabetical order.
+// Feeds in no folder come last.
+func buildDigest(s *state, user database.User, since time.Time) (digest.Digest, error) {
+	dg := digest.Digest{User: user.Name, Since: since}
+
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return dg, err
+	}
+	follows := map[uuid.UUID]database.GetFeedFollowsForUserRow{}
+	for _, ff := range feedFollows {
+		follows[ff.FeedID] = ff
+	}
+
+	feeds := map[uuid.UUID]*digest.Feed{}
+	before := uuid.NullUUID{}
+	for {
+		posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID:     user.ID,
+			From:       sql.NullTime{Time: since, Valid: true},
+			Sort:       "fetched",
+			UnreadOnly: true,
+			Before:     before,
+			Limit:      digestPageSize,
+		})
+		if err != nil {
+			return dg, err
+		}
+		for _, p := range posts {
+			feed, ok := feeds[p.FeedID]
+			if !ok {
+				feed = &digest.Feed{Name: follows[p.FeedID].FeedName}
+				feeds[p.FeedID] = feed
+			}
+			feed.Posts = append(feed.Posts, digest.Post{
+				Title:     p.Title,
+				URL:       p.Url,
+				Published: p.PublishedAt,
+				Summary:   htmltext.Summary(p.Description, digestSummaryLength),
+			})
+		}
+		if len(posts) < digestPageSize {
+			break
+		}
+		before = uuid.NullUUID{UUID: posts[len(posts)-1].ID, Valid: true}
+	}
+
+	groups := map[string]*digest.Group{}
+	for feedID, feed := range feeds {
+		folder := follows[feedID].FolderName.String
+		group, ok := groups[folder]
+		if !ok {
+			group = &digest.Group{Folder: folder}
+			groups[folder] = group
+		}
+		group.Feeds = append(group.Feeds, *feed)
+	}
+	for _, group := range groups {
+		slices.SortFunc(group.Feeds, func(a, b digest.Feed) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		dg.Groups = append(dg.Groups, *group)
+	}
+	slices.SortFunc(dg.Groups, func(a, b digest.Group) int {
+		if (a.Folder == "") != (b.Folder == "") {
+			if a.Folder == "" {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.Folder, b.Folder)
+	})
+	return dg, nil
+}
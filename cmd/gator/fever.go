@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/fever"
+	"golang.org/x/term"
+)
+
+func handlerFever(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		_, err := s.db.GetFeverKey(context.Background(), user.ID)
+		if err == sql.ErrNoRows {
+			fmt.Printf("The Fever and Google Reader APIs are off for %s\n", user.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The Fever and Google Reader APIs are on for %s\n", user.Name)
+		return nil
+	}
+
+	switch cmd.args[0] {
+	case "on":
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		err = s.db.SetFeverKey(context.Background(), database.SetFeverKeyParams{
+			UserID:    user.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			ApiKey:    fever.Key(user.Name, password),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The Fever and Google Reader APIs are now on for %s\n", user.Name)
+		fmt.Println("In your app, use the address of gator serve followed by /fever/ or /greader, your user name and this password.")
+		return nil
+	case "off":
+		n, err := s.db.DeleteFeverKey(context.Background(), user.ID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			fmt.Printf("The Fever and Google Reader APIs were already off for %s\n", user.Name)
+			return nil
+		}
+		fmt.Printf("The Fever and Google Reader APIs are now off for %s\n", user.Name)
+		return nil
+	default:
+		return cmd.usageError("expected 'on' or 'off', got '%s'", cmd.args[0])
+	}
+}
+
+// readPassword asks for a new password twice in the terminal, or reads it
+// from the first line of stdin when it isn't a terminal.
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", fmt.Errorf("no password given on stdin")
+		}
+		if err != nil && line == "" {
+			return "", err
+		}
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(password) == 0 {
+		return "", fmt.Errorf("the password can't be empty")
+	}
+	fmt.Fprint(os.Stderr, "Password again: ")
+	again, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(again) != string(password) {
+		return "", fmt.Errorf("the passwords don't match")
+	}
+	return string(password), nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/google/uuid"
+)
+
+func handlerFolder(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return listFolders(s, user)
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "list":
+		return listFolders(s, user)
+	case "create":
+		if len(args) != 1 {
+			return cmd.usageError("'folder create' expects 1 argument (name)")
+		}
+		_, err := s.db.CreateFolder(context.Background(), database.CreateFolderParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      args[0],
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Folder '%s' has been created\n", args[0])
+	case "rename":
+		if len(args) != 2 {
+			return cmd.usageError("'folder rename' expects 2 arguments (name, new_name)")
+		}
+		folder, err := getFolder(s, user, args[0])
+		if err != nil {
+			return err
+		}
+		err = s.db.RenameFolder(context.Background(), database.RenameFolderParams{
+			ID:   folder.ID,
+			Name: args[1],
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Folder '%s' has been renamed to '%s'\n", args[0], args[1])
+	case "delete":
+		if len(args) != 1 {
+			return cmd.usageError("'folder delete' expects 1 argument (name)")
+		}
+		folder, err := getFolder(s, user, args[0])
+		if err != nil {
+			return err
+		}
+		err = s.db.DeleteFolder(context.Background(), folder.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Folder '%s' has been deleted, its feeds are still followed\n", args[0])
+	case "move":
+		if len(args) == 0 {
+			return cmd.usageError("'folder move' expects at least 1 argument (feed_url)")
+		}
+		feed, err := s.db.GetFeed(context.Background(), args[0])
+		if err != nil {
+			return fmt.Errorf("feed '%s' not found", args[0])
+		}
+		folderID := uuid.NullUUID{}
+		if len(args) > 1 {
+			folder, err := getFolder(s, user, args[1])
+			if err != nil {
+				return err
+			}
+			folderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+		}
+		moved, err := s.db.SetFeedFollowFolder(context.Background(), database.SetFeedFollowFolderParams{
+			UserID:   user.ID,
+			FeedID:   feed.ID,
+			FolderID: folderID,
+		})
+		if err != nil {
+			return err
+		}
+		if moved == 0 {
+			return fmt.Errorf("you're not following '%s'", args[0])
+		}
+		if folderID.Valid {
+			fmt.Printf("Moved '%s' to folder '%s'\n", feed.Name, args[1])
+		} else {
+			fmt.Printf("Moved '%s' out of its folder\n", feed.Name)
+		}
+	default:
+		return cmd.usageError("unknown folder subcommand '%s', expected one of: list, create, rename, delete, move", cmd.args[0])
+	}
+	return nil
+}
+
+func listFolders(s *state, user database.User) error {
+	folders, err := s.db.GetFoldersForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	l := output.NewList("name", "created_at")
+	for _, folder := range folders {
+		l.Add(folder.Name, folder.CreatedAt)
+	}
+
+	return s.render(l, func() {
+		for _, folder := range folders {
+			fmt.Println(folder.Name)
+		}
+	})
+}
+
+func getFolder(s *state, user database.User, name string) (database.Folder, error) {
+	folder, err := s.db.GetFolder(context.Background(), database.GetFolderParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err == sql.ErrNoRows {
+		return folder, fmt.Errorf("folder '%s' not found", name)
+	}
+	return folder, err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/readability"
+)
+
+// maxArticleSize limits how much of a page is downloaded to extract its
+// article.
+const maxArticleSize = 5 << 20
+
+func handlerFullText(s *state, cmd command, user database.User) error {
+	feed, err := s.db.GetFeed(context.Background(), cmd.args[0])
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("feed '%s' not found", cmd.args[0])
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.db.GetFeedFollow(context.Background(), database.GetFeedFollowParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("you don't follow the feed '%s'", feed.Url)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.args) == 1 {
+		if feed.FullText {
+			fmt.Printf("Full text is on for %s\n", feed.Name)
+		} else {
+			fmt.Printf("Full text is off for %s\n", feed.Name)
+		}
+		return nil
+	}
+
+	var fullText bool
+	switch cmd.args[1] {
+	case "on":
+		fullText = true
+	case "off":
+		fullText = false
+	default:
+		return cmd.usageError("expected 'on' or 'off', got '%s'", cmd.args[1])
+	}
+	err = s.db.SetFeedFullText(context.Background(), database.SetFeedFullTextParams{
+		ID:       feed.ID,
+		FullText: fullText,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Full text is now %s for %s\n", cmd.args[1], feed.Name)
+	return nil
+}
+
+// storeFullText downloads a post's page and stores the article extracted from
+// it as the post's content. Pages that can't be downloaded or don't contain an
+// article are only logged, so that they don't stop the aggregator. It returns
+// the post with its new content.
+func storeFullText(s *state, post database.Post) database.Post {
+	content, err := fetchFullText(context.Background(), post.Url)
+	if err != nil {
+		log.Printf("Couldn't get the full text of %s: %v", post.Url, err)
+		return post
+	}
+	err = s.db.SetPostContent(context.Background(), database.SetPostContentParams{
+		ID:      post.ID,
+		Content: content,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	post.Content = content
+	return post
+}
+
+func fetchFullText(ctx context.Context, pageURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	client := http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", res.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return "", fmt.Errorf("not an HTML page: %s", mediaType)
+	}
+	return readability.Extract(io.LimitReader(res.Body, maxArticleSize))
+}
@@ -4,110 +4,549 @@ import (
 	"context"
 	"database/sql"
 	"encoding/xml"
+	"flag"
 	"fmt"
 	"html"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/R0Xps/gatorcli/internal/alerts"
 	"github.com/R0Xps/gatorcli/internal/config"
 	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/dates"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/R0Xps/gatorcli/internal/rules"
+	"github.com/R0Xps/gatorcli/internal/tui"
+	"github.com/R0Xps/gatorcli/internal/webhook"
 	"github.com/araddon/dateparse"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	conf, err := config.Read()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	db, err := sql.Open("postgres", conf.Db_url)
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	dbQueries := database.New(db)
-
-	st := state{
-		db:     dbQueries,
-		config: &conf,
-	}
 	cmds := commands{
 		commands: map[string]func(*state, command) error{},
+		info:     map[string]commandInfo{},
 	}
+	registerCommands(&cmds)
 
-	cmds.register("login", handlerLogin)
-	cmds.register("register", handlerRegister)
-	cmds.register("reset", handlerReset)
-	cmds.register("users", handlerUsers)
-	cmds.register("agg", handlerAgg)
-	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed))
-	cmds.register("feeds", handlerFeeds)
-	cmds.register("follow", middlewareLoggedIn(handlerFollow))
-	cmds.register("following", middlewareLoggedIn(handlerFollowing))
-	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow))
-	cmds.register("browse", handlerBrowse)
-
-	args := os.Args
+	// Shell completion needs to see the global flags to complete them.
+	format, args, err := output.Text, os.Args, error(nil)
+	if len(args) < 2 || args[1] != "__complete" {
+		format, args, err = parseGlobalFlags(os.Args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gator:", err)
+			os.Exit(2)
+		}
+	}
 
 	if len(args) < 2 {
-		log.Fatal("Missing argument (command name)")
+		cmds.printHelp(os.Stderr)
+		os.Exit(2)
 	}
 
+	st := state{
+		output: format,
+	}
 	cmd := command{
 		name: args[1],
 		args: args[2:],
 	}
 
 	err = cmds.run(&st, cmd)
+	if uerr, ok := err.(usageError); ok {
+		fmt.Fprintln(os.Stderr, "gator:", uerr)
+		if uerr.command != "" {
+			fmt.Fprintln(os.Stderr, "Usage:", cmds.usage(uerr.command))
+			fmt.Fprintf(os.Stderr, "Run 'gator help %s' for more information.\n", uerr.command)
+		} else {
+			fmt.Fprintln(os.Stderr, "Run 'gator help' for a list of commands.")
+		}
+		os.Exit(2)
+	}
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintln(os.Stderr, "gator:", err)
+		os.Exit(1)
 	}
+}
 
+func registerCommands(cmds *commands) {
+	cmds.register("register", handlerRegister, commandInfo{
+		args:        "<user_name>",
+		description: "Create a new user and log in as them.",
+		minArgs:     1,
+		maxArgs:     1,
+	})
+	cmds.register("login", handlerLogin, commandInfo{
+		args:        "<user_name>",
+		description: "Switch to an existing user.",
+		minArgs:     1,
+		maxArgs:     1,
+		complete:    completeUsers,
+	})
+	cmds.register("users", handlerUsers, commandInfo{
+		description: "List all registered users, indicating which one is currently logged in.",
+		maxArgs:     0,
+	})
+	cmds.register("reset", handlerReset, commandInfo{
+		description: "Delete all users, along with their feeds, follows and posts.",
+		maxArgs:     0,
+	})
+	cmds.register("agg", handlerAgg, commandInfo{
+		args: "<time_between_requests>",
+		description: "Start the aggregator, which keeps collecting posts until it's stopped with Ctrl+C.\n" +
+			"Every time_between_requests (e.g. 1m), it fetches the least recently fetched feed.",
+		minArgs: 1,
+		maxArgs: 1,
+	})
+	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed), commandInfo{
+		args:        "<feed_name> <feed_url>",
+		description: "Add a new feed to the database and follow it.",
+		minArgs:     2,
+		maxArgs:     2,
+	})
+	cmds.register("feeds", handlerFeeds, commandInfo{
+		description: "List all feeds in the database.",
+		maxArgs:     0,
+	})
+	cmds.register("follow", middlewareLoggedIn(handlerFollow), commandInfo{
+		args:        "<feed_url>",
+		description: "Follow a feed that has been added to the database.",
+		minArgs:     1,
+		maxArgs:     1,
+		complete:    completeFeeds,
+	})
+	cmds.register("following", middlewareLoggedIn(handlerFollowing), commandInfo{
+		description: "List the feeds you follow, grouped by folder.",
+		maxArgs:     0,
+	})
+	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow), commandInfo{
+		args:        "<feed_url>",
+		description: "Unfollow a feed.",
+		minArgs:     1,
+		maxArgs:     1,
+		complete:    completeFollowedFeeds,
+	})
+	cmds.register("fulltext", middlewareLoggedIn(handlerFullText), commandInfo{
+		args: "<feed_url> [on|off]",
+		description: "Turn full text on or off for a feed you follow, or show whether it's on.\n" +
+			"With full text on, the aggregator downloads the page of each new post and\n" +
+			"keeps the article extracted from it, for feeds that only carry a summary.\n" +
+			"The article is shown by show and tui, and searched by search.",
+		minArgs: 1,
+		maxArgs: 2,
+		complete: func(s *state, args []string) []string {
+			switch len(args) {
+			case 0:
+				return completeFollowedFeeds(s, args)
+			case 1:
+				return []string{"on", "off"}
+			}
+			return nil
+		},
+	})
+	cmds.register("browse", middlewareLoggedIn(handlerBrowse), commandInfo{
+		args: "[limit]",
+		description: "List the posts from the feeds you follow, newest first.\n" +
+			"Shows up to limit (default 2) posts. Pass the ID of the first or last one to\n" +
+			"--after or --before to see the next posts.",
+		maxArgs: 1,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("before", "", "show posts older than the post with this `ID`")
+			fs.String("after", "", "show posts newer than the post with this `ID`")
+			fs.Int("page", 1, "`page` of posts to show, counting from the newest post (or from --before)")
+			fs.String("feed", "", "only show posts from the feed with this `URL or name`")
+			fs.String("folder", "", "only show posts from the feeds in this `folder`")
+			fs.String("tag", "", "only show posts tagged with this `tag`")
+			fs.String("since", "", "only show posts from the last `duration`, e.g. 24h")
+			fs.String("from", "", "only show posts from this `date` onwards")
+			fs.String("to", "", "only show posts up to this `date`, including the whole day if it has no time")
+			fs.String("search", "", "only show posts whose title or description contain this `text`")
+			fs.Bool("unread", false, "only show posts you haven't read")
+			fs.Bool("starred", false, "only show posts you starred")
+			fs.String("sort", "published", "order posts by the date they were 'published' or 'fetched'")
+		},
+		completeFlags: map[string]func(*state, []string) []string{
+			"feed":   completeFollowedFeeds,
+			"folder": completeFolders,
+			"tag":    completeTags,
+			"sort":   completeWords("published", "fetched"),
+		},
+	})
+	cmds.register("search", middlewareLoggedIn(handlerSearch), commandInfo{
+		args: "<query> [limit]",
+		description: "Full-text search of the posts from the feeds you follow.\n" +
+			"Shows up to limit (default 10) results, best matches first. The query supports\n" +
+			"\"quoted phrases\", or, and -excluded words.",
+		minArgs: 1,
+		maxArgs: 2,
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("all", false, "search the posts of all feeds, not just the followed ones")
+			fs.String("folder", "", "only search the posts from the feeds in this `folder`")
+		},
+		completeFlags: map[string]func(*state, []string) []string{
+			"folder": completeFolders,
+		},
+	})
+	cmds.register("open", middlewareLoggedIn(handlerOpen), commandInfo{
+		args:        "<post_id>",
+		description: "Open a post in your web browser ($BROWSER if set) and mark it read.",
+		minArgs:     1,
+		maxArgs:     1,
+	})
+	cmds.register("show", middlewareLoggedIn(handlerShow), commandInfo{
+		args: "<post_id>",
+		description: "Show a post in the terminal and mark it read.\n" +
+			"The description is shown as wrapped text, with its links listed at the end.",
+		minArgs: 1,
+		maxArgs: 1,
+		flags: func(fs *flag.FlagSet) {
+			fs.Int("width", 0, "wrap lines at this many `columns` (default: the terminal width, at most 100)")
+		},
+	})
+	cmds.register("archive", middlewareLoggedIn(handlerArchive), commandInfo{
+		args: "<post_id> | --starred",
+		description: "Save a local copy of a post, or of all your starred posts, to read offline.\n" +
+			"Copies are standalone HTML or Markdown files of the post's article, with its\n" +
+			"images, saved in the directory set by archive_dir in the config file\n" +
+			"(~/gator-archive by default). show reads the local copy of archived posts.",
+		maxArgs: 1,
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("starred", false, "archive all your starred posts that aren't archived yet")
+			fs.String("format", "html", "save posts as 'html' or 'markdown'")
+			fs.String("dir", "", "save posts in this `directory` instead of the configured one")
+			fs.Bool("no-images", false, "link to the images instead of saving them")
+		},
+		completeFlags: map[string]func(*state, []string) []string{
+			"format": completeWords("html", "markdown"),
+		},
+	})
+	cmds.register("tui", middlewareLoggedIn(handlerTUI), commandInfo{
+		description: "Read the posts from the feeds you follow in a full-screen terminal reader.\n" +
+			"Pick a feed or folder on the left, then a post to preview it. Press r to mark\n" +
+			"the post read or unread, s to star it, o to open it in your browser ($BROWSER),\n" +
+			"and q to quit.",
+		maxArgs: 0,
+	})
+	cmds.register("folder", middlewareLoggedIn(handlerFolder), commandInfo{
+		args: "[list | create <name> | rename <name> <new_name> | delete <name> | move <feed_url> [name]]",
+		description: "Organize the feeds you follow into folders.\n" +
+			"Deleting a folder keeps its feeds followed, and moving a feed without a folder\n" +
+			"name takes it out of its folder.",
+		maxArgs:  3,
+		complete: completeFolderArgs,
+	})
+	cmds.register("tag", middlewareLoggedIn(handlerTag), commandInfo{
+		args:        "<post_id> [tag...]",
+		description: "Tag a post with one or more tags, then list all of its tags.",
+		minArgs:     1,
+		maxArgs:     -1,
+		complete:    completePostTags,
+	})
+	cmds.register("untag", middlewareLoggedIn(handlerUntag), commandInfo{
+		args:        "<post_id> <tag...>",
+		description: "Remove one or more tags from a post.",
+		minArgs:     2,
+		maxArgs:     -1,
+		complete:    completePostTags,
+	})
+	cmds.register("tags", middlewareLoggedIn(handlerTags), commandInfo{
+		args:        "[prefix]",
+		description: "List your tags, optionally only the ones starting with prefix.",
+		maxArgs:     1,
+		complete:    completeTags,
+	})
+	cmds.register("import", middlewareLoggedIn(handlerImport), commandInfo{
+		args: "opml <file>",
+		description: "Import the feeds in an OPML file.\n" +
+			"Missing feeds are added to the database, and all of them are followed. Nested\n" +
+			"outlines become folders.",
+		minArgs:  2,
+		maxArgs:  2,
+		complete: completeFormat,
+	})
+	cmds.register("export", handlerExport, commandInfo{
+		args:        "opml [file]",
+		description: "Export the feeds you follow as an OPML 2.0 file, or print it if no file is given.",
+		minArgs:     1,
+		maxArgs:     2,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("user", "", "export the feeds followed by this `user` instead")
+		},
+		complete: completeFormat,
+		completeFlags: map[string]func(*state, []string) []string{
+			"user": completeUsers,
+		},
+	})
+	cmds.register("publish", handlerPublish, commandInfo{
+		args: "[file]",
+		description: "Publish the latest posts from the feeds you follow as an Atom or RSS feed, or\n" +
+			"print it if no file is given. serve also publishes them at\n" +
+			"/users/<name>/feed.xml, see feedtoken.",
+		maxArgs: 1,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("user", "", "publish the posts followed by this `user` instead")
+			fs.String("format", "atom", "publish an 'atom' or 'rss' feed")
+			fs.String("folder", "", "only publish posts from the feeds in this `folder`")
+			fs.Int("limit", 50, "publish this many of the latest posts")
+		},
+		completeFlags: map[string]func(*state, []string) []string{
+			"user":   completeUsers,
+			"format": completeWords("atom", "rss"),
+			"folder": completeFolders,
+		},
+	})
+	cmds.register("feedtoken", middlewareLoggedIn(handlerFeedToken), commandInfo{
+		args: "[new|off]",
+		description: "Show, replace or remove the token that lets feed readers fetch the feeds serve\n" +
+			"publishes for you, when serve has a token. It's added to the feed's URL and only\n" +
+			"gives access to your published feeds, never to the API.",
+		maxArgs: 1,
+		complete: func(s *state, args []string) []string {
+			if len(args) == 0 {
+				return []string{"new", "off"}
+			}
+			return nil
+		},
+	})
+	cmds.register("planet", handlerPlanet, commandInfo{
+		args: "[feed_url ...]",
+		description: "Render a static Planet-style site from the posts of the given feeds, or of all\n" +
+			"the feeds you follow: an index of the latest posts, a page per feed and per\n" +
+			"day, and an Atom feed of them all. Templates in the --templates directory\n" +
+			"replace the default ones in internal/planet/templates with the same name.",
+		maxArgs: -1,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("out", "site", "render the site into this `directory`")
+			fs.String("user", "", "use the feeds followed by this `user` instead")
+			fs.String("folder", "", "only use the feeds in this `folder`")
+			fs.Int("days", 14, "include the posts from this many days")
+			fs.String("title", "", "the site's `title` (default \"Planet <user>\")")
+			fs.String("url", "", "the `address` the site is hosted at, for links in its Atom feed")
+			fs.String("templates", "", "use the templates in this `directory`")
+		},
+		complete: completeFollowedFeeds,
+		completeFlags: map[string]func(*state, []string) []string{
+			"user":   completeUsers,
+			"folder": completeFolders,
+		},
+	})
+	cmds.register("digest", handlerDigest, commandInfo{
+		description: "Email a digest of the unread posts from the feeds you follow, grouped by\n" +
+			"folder and feed, through the mail server set as smtp in the config file.",
+		maxArgs: 0,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("user", "", "send the digest of this `user` instead")
+			fs.String("since", "24h", "include the posts fetched in the last `duration`")
+			fs.String("to", "", "send the digest to this `address` instead of the user's")
+			fs.Bool("dry-run", false, "print the email instead of sending it")
+		},
+		completeFlags: map[string]func(*state, []string) []string{
+			"user": completeUsers,
+		},
+	})
+	cmds.register("webhooks", middlewareLoggedIn(handlerWebhooks), commandInfo{
+		args: "[list | add <url> [feed_url] | remove <id> | test <id> | log <id>]",
+		description: "Manage webhooks, which agg notifies of the new posts it collects.\n" +
+			"A webhook gets a signed JSON POST request with the new posts of each fetch of\n" +
+			"the given feed, or of any feed you follow if no feed is given. Failed requests\n" +
+			"are retried for about 15 hours. test sends a ping, and log lists the latest\n" +
+			"deliveries.",
+		maxArgs:  3,
+		complete: completeWebhookArgs,
+	})
+	cmds.register("rule", middlewareLoggedIn(handlerRule), commandInfo{
+		args: "[list | add | remove <id> | test [id] | apply [id] | unhide <post_id>]",
+		description: "Manage rules, which act on the posts whose title or content match regexes.\n" +
+			"agg applies them to new posts, and apply applies them to the posts you already\n" +
+			"have. The action is hide, mark-read, star or tag:<name>. test lists the posts a\n" +
+			"saved rule, or the rule given with the flags, matches without acting on them.\n" +
+			"unhide shows a post hidden by a rule again.",
+		maxArgs: 2,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("feed", "", "only match the posts of the feed at this `url`")
+			fs.String("title-regex", "", "match the posts whose title matches this `regex`")
+			fs.String("content-regex", "", "match the posts whose description or content matches this `regex`")
+			fs.String("action", "", "take this `action` on matching posts: hide, mark-read, star or tag:<name>")
+		},
+		complete: completeRuleArgs,
+		completeFlags: map[string]func(*state, []string) []string{
+			"feed":   completeFollowedFeeds,
+			"action": completeWords("hide", "mark-read", "star", "tag:"),
+		},
+	})
+	cmds.register("alerts", middlewareLoggedIn(handlerAlerts), commandInfo{
+		args: "[list | watch <word> | unwatch <word> | words | clear | email [address|off]]",
+		description: "List the alerts raised when agg finds your watch words in new posts, newest\n" +
+			"first, with the matches wrapped in **. watch adds a watch word, matched as a\n" +
+			"whole word regardless of case, or as a regex with --regex. --webhook and --email\n" +
+			"also send its alerts to your webhooks and to your email address, which email\n" +
+			"shows, sets or removes. words lists the watch words, and clear removes all\n" +
+			"alerts.",
+		maxArgs: 2,
+		flags: func(fs *flag.FlagSet) {
+			fs.Int("limit", 20, "list at most `count` alerts")
+			fs.String("word", "", "only list the alerts of this watch `word`")
+			fs.Bool("regex", false, "watch for a regular expression instead of a word")
+			fs.Bool("webhook", false, "send the word's alerts to your webhooks")
+			fs.Bool("email", false, "email the word's alerts")
+		},
+		complete: completeAlertsArgs,
+		completeFlags: map[string]func(*state, []string) []string{
+			"word": completeWatchWords,
+		},
+	})
+	cmds.register("backup", handlerBackup, commandInfo{
+		args:        "<file>",
+		description: "Save everything in the database to a versioned JSON Lines file.",
+		minArgs:     1,
+		maxArgs:     1,
+	})
+	cmds.register("restore", handlerRestore, commandInfo{
+		args: "<file>",
+		description: "Merge a file made by backup into the database.\n" +
+			"Existing users, feeds, posts, folders and tags are kept, so restoring the same\n" +
+			"file twice changes nothing.",
+		minArgs: 1,
+		maxArgs: 1,
+	})
+	cmds.register("serve", handlerServe, commandInfo{
+		description: "Serve a web reader and a JSON REST API until stopped with Ctrl+C.\n" +
+			"The web reader is at /, and the API is versioned under /v1/. If a token is set\n" +
+			"with --token or $GATOR_API_TOKEN, logging in to the web reader asks for it, and\n" +
+			"API requests must send it in an \"Authorization: Bearer\" header.",
+		maxArgs: 0,
+		flags: func(fs *flag.FlagSet) {
+			fs.String("addr", "", "listen on this `address` (default \":8080\", or \"127.0.0.1:8080\" without a token)")
+			fs.String("token", "", "require this `token` to log in and on every API request")
+		},
+	})
+	cmds.register("fever", middlewareLoggedIn(handlerFever), commandInfo{
+		args: "[on|off]",
+		description: "Turn the Fever and Google Reader APIs on or off for you, or show whether they're on.\n" +
+			"Turning them on asks for a password, which apps log in with, along with your\n" +
+			"user name, at the /fever/ (Reeder, Unread, ...) or /greader (NetNewsWire,\n" +
+			"FeedMe, Readrops, ...) address of gator serve.",
+		maxArgs: 1,
+		complete: func(s *state, args []string) []string {
+			if len(args) == 0 {
+				return []string{"on", "off"}
+			}
+			return nil
+		},
+	})
+	cmds.register("completion", handlerCompletion, commandInfo{
+		args: "bash|zsh|fish",
+		description: "Print the shell completion script for bash, zsh or fish.\n" +
+			"To enable completion, add the line for your shell to its startup file:\n" +
+			"  bash: source <(gator completion bash)\n" +
+			"  zsh:  source <(gator completion zsh)\n" +
+			"  fish: gator completion fish | source",
+		minArgs:  1,
+		maxArgs:  1,
+		complete: completeWords("bash", "zsh", "fish"),
+		offline:  true,
+	})
+	cmds.register("__complete", cmds.handlerComplete, commandInfo{
+		args:        "[word...]",
+		description: "Print the completions of the last word of a gator command line, used by the shell completion scripts.",
+		maxArgs:     -1,
+		rawArgs:     true,
+		offline:     true,
+		hidden:      true,
+	})
 }
 
 type state struct {
 	db     *database.Queries
+	conn   *sql.DB
 	config *config.Config
+	output output.Format
 }
 
-type command struct {
-	name string
-	args []string
-}
+// connect reads the config file and opens the database it points to.
+func (s *state) connect() error {
+	if s.db != nil {
+		return nil
+	}
 
-type commands struct {
-	commands map[string]func(*state, command) error
-}
+	conf, err := config.Read()
+	if err != nil {
+		return err
+	}
 
-func (c *commands) run(s *state, cmd command) error {
-	commandHandler, ok := c.commands[cmd.name]
-	if !ok {
-		return fmt.Errorf("command '%s' not found", cmd.name)
+	db, err := sql.Open("postgres", conf.Db_url)
+	if err != nil {
+		return err
 	}
-	return commandHandler(s, cmd)
+
+	s.config = &conf
+	s.conn = db
+	s.db = database.New(db)
+	return nil
 }
 
-func (c *commands) register(name string, f func(*state, command) error) {
-	c.commands[name] = f
+// render prints a list in the output format chosen with --output, calling
+// text to print it when the default text format is chosen.
+func (s *state) render(l *output.List, text func()) error {
+	if s.output == output.Text {
+		text()
+		return nil
+	}
+	return output.Render(os.Stdout, s.output, l)
 }
 
-func handlerLogin(s *state, cmd command) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf("command 'login' expects 1 argument (username)")
+// parseGlobalFlags removes the flags that apply to every command from args,
+// wherever they appear before a "--" argument, and returns the chosen output
+// format along with the remaining arguments. The "--" is kept so that the
+// command's own flags stop there too.
+func parseGlobalFlags(args []string) (output.Format, []string, error) {
+	format := output.Text
+	rest := []string{}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return format, append(rest, args[i:]...), nil
+		}
+		value, ok := "", false
+		switch {
+		case args[i] == "--output" || args[i] == "-o":
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("flag %s needs an argument", args[i])
+			}
+			value, ok = args[i+1], true
+			i++
+		case strings.HasPrefix(args[i], "--output="):
+			value, ok = strings.TrimPrefix(args[i], "--output="), true
+		case strings.HasPrefix(args[i], "-o="):
+			value, ok = strings.TrimPrefix(args[i], "-o="), true
+		}
+		if !ok {
+			rest = append(rest, args[i])
+			continue
+		}
+		var err error
+		format, err = output.ParseFormat(value)
+		if err != nil {
+			return "", nil, err
+		}
 	}
+	return format, rest, nil
+}
 
+func handlerLogin(s *state, cmd command) error {
 	_, err := s.db.GetUser(context.Background(), cmd.args[0])
-
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user '%s' doesn't exist", cmd.args[0])
+	}
 	if err != nil {
-		log.Fatal("User doesn't exist")
+		return err
 	}
 
 	err = s.config.SetUser(cmd.args[0])
@@ -119,9 +558,6 @@ func handlerLogin(s *state, cmd command) error {
 }
 
 func handlerRegister(s *state, cmd command) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf("command 'register' expects 1 argument (username)")
-	}
 	usr, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
@@ -136,9 +572,12 @@ func handlerRegister(s *state, cmd command) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("User has been created")
-	fmt.Println(usr)
-	return nil
+
+	l := output.NewList("id", "name", "created_at")
+	l.Add(usr.ID, usr.Name, usr.CreatedAt)
+	return s.render(l, func() {
+		fmt.Printf("User '%s' has been created\n", usr.Name)
+	})
 }
 
 func handlerReset(s *state, cmd command) error {
@@ -157,30 +596,35 @@ func handlerUsers(s *state, cmd command) error {
 		return err
 	}
 
+	l := output.NewList("name", "current", "created_at")
 	for _, user := range users {
-		fmt.Print(user.Name)
-		if user.Name == s.config.Current_user_name {
-			fmt.Print(" (current)")
-		}
-		fmt.Println()
+		l.Add(user.Name, user.Name == s.config.Current_user_name, user.CreatedAt)
 	}
 
-	return nil
+	return s.render(l, func() {
+		for _, user := range users {
+			fmt.Print(user.Name)
+			if user.Name == s.config.Current_user_name {
+				fmt.Print(" (current)")
+			}
+			fmt.Println()
+		}
+	})
 }
 
 func handlerAgg(s *state, cmd command) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf("command 'agg' expects 1 argument (timeBetweenReqs)")
-	}
 	timeBetweenRequests, err := time.ParseDuration(cmd.args[0])
 	if err != nil {
-		return err
+		return cmd.usageError("invalid duration '%s'", cmd.args[0])
 	}
 	fmt.Printf("Collecting feeds every %v\n", timeBetweenRequests)
 
 	ticker := time.NewTicker(timeBetweenRequests)
 	for ; ; <-ticker.C {
 		scrapeFeeds(s)
+		if err := webhook.DeliverDue(context.Background(), s.db); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
@@ -237,10 +681,6 @@ func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 }
 
 func handlerAddFeed(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 2 {
-		return fmt.Errorf("command 'addfeed' expects 2 arguments (feedName, feedURL)")
-	}
-
 	feed, err := s.db.AddFeed(context.Background(), database.AddFeedParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
@@ -253,8 +693,6 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 		return err
 	}
 
-	fmt.Println(feed)
-
 	_, err = s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
@@ -265,7 +703,12 @@ func handlerAddFeed(s *state, cmd command, user database.User) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	l := output.NewList("id", "name", "url", "created_at")
+	l.Add(feed.ID, feed.Name, feed.Url, feed.CreatedAt)
+	return s.render(l, func() {
+		fmt.Printf("Feed '%s' has been added and followed\n", feed.Name)
+	})
 }
 
 func handlerFeeds(s *state, cmd command) error {
@@ -274,21 +717,29 @@ func handlerFeeds(s *state, cmd command) error {
 		return err
 	}
 
-	for _, feed := range feeds {
+	l := output.NewList("id", "name", "url", "site_url", "added_by", "last_fetched_at")
+	addedBy := make([]string, len(feeds))
+	for i, feed := range feeds {
 		user, err := s.db.GetUserById(context.Background(), feed.UserID)
 		if err != nil {
 			return err
 		}
-		fmt.Println(feed.Name, feed.Url, user.Name)
+		addedBy[i] = user.Name
+		var lastFetchedAt *time.Time
+		if feed.LastFetchedAt.Valid {
+			lastFetchedAt = &feed.LastFetchedAt.Time
+		}
+		l.Add(feed.ID, feed.Name, feed.Url, feed.SiteUrl, user.Name, lastFetchedAt)
 	}
-	return nil
+
+	return s.render(l, func() {
+		for i, feed := range feeds {
+			fmt.Println(htmltext.Clean(feed.Name), htmltext.Clean(feed.Url), addedBy[i])
+		}
+	})
 }
 
 func handlerFollow(s *state, cmd command, user database.User) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf("command 'follow' expects 1 argument (url)")
-	}
-
 	feed, err := s.db.GetFeed(context.Background(), cmd.args[0])
 	if err != nil {
 		return err
@@ -305,7 +756,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 		return err
 	}
 
-	fmt.Println(ff.FeedName, ff.UserName)
+	fmt.Println(htmltext.Clean(ff.FeedName), ff.UserName)
 	return nil
 }
 
@@ -315,17 +766,30 @@ func handlerFollowing(s *state, cmd command, user database.User) error {
 		return err
 	}
 
+	l := output.NewList("feed_name", "feed_url", "folder", "followed_at")
 	for _, feedFollow := range feedFollows {
-		fmt.Println(feedFollow.FeedName)
+		var folder any
+		if feedFollow.FolderName.Valid {
+			folder = feedFollow.FolderName.String
+		}
+		l.Add(feedFollow.FeedName, feedFollow.FeedUrl, folder, feedFollow.CreatedAt)
 	}
-	return nil
+
+	return s.render(l, func() {
+		for i, feedFollow := range feedFollows {
+			if !feedFollow.FolderName.Valid {
+				fmt.Println(htmltext.Clean(feedFollow.FeedName))
+				continue
+			}
+			if i == 0 || feedFollow.FolderName != feedFollows[i-1].FolderName {
+				fmt.Printf("%s/\n", feedFollow.FolderName.String)
+			}
+			fmt.Println("  " + htmltext.Clean(feedFollow.FeedName))
+		}
+	})
 }
 
 func handlerUnfollow(s *state, cmd command, user database.User) error {
-	if len(cmd.args) == 0 {
-		return fmt.Errorf("command 'unfollow' expects 1 argument (url)")
-	}
-
 	err := s.db.DeleteFeedFollow(context.Background(), database.DeleteFeedFollowParams{
 		UserID: user.ID,
 		Url:    cmd.args[0],
@@ -336,34 +800,240 @@ func handlerUnfollow(s *state, cmd command, user database.User) error {
 	return nil
 }
 
-func handlerBrowse(s *state, cmd command) error {
+func handlerBrowse(s *state, cmd command, user database.User) error {
+	before := cmd.stringFlag("before")
+	after := cmd.stringFlag("after")
+	page := cmd.intFlag("page")
+	sort := cmd.stringFlag("sort")
+	since := cmd.stringFlag("since")
+	from := cmd.stringFlag("from")
+	to := cmd.stringFlag("to")
+
 	limit := 2
-	var err error
 	if len(cmd.args) > 0 {
+		var err error
 		limit, err = strconv.Atoi(cmd.args[0])
+		if err != nil || limit < 1 {
+			return cmd.usageError("limit must be a positive number")
+		}
+	}
+	if page < 1 {
+		return cmd.usageError("page must be a positive number")
+	}
+	if before != "" && after != "" {
+		return cmd.usageError("--before and --after can't be used together")
+	}
+	if after != "" && page != 1 {
+		return cmd.usageError("--page can't be used together with --after")
+	}
+	if sort != "published" && sort != "fetched" {
+		return cmd.usageError("--sort must be either 'published' or 'fetched'")
+	}
+	if since != "" && from != "" {
+		return cmd.usageError("--since and --from can't be used together")
+	}
+
+	fromTime := sql.NullTime{}
+	if since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil {
+			return cmd.usageError("invalid duration '%s'", since)
+		}
+		fromTime = sql.NullTime{Time: time.Now().Add(-d), Valid: true}
+	}
+	if from != "" {
+		t, err := dates.Start(from)
+		if err != nil {
+			return cmd.usageError("invalid date '%s'", from)
+		}
+		fromTime = sql.NullTime{Time: t, Valid: true}
+	}
+	toTime := sql.NullTime{}
+	if to != "" {
+		t, err := dates.End(to)
+		if err != nil {
+			return cmd.usageError("invalid date '%s'", to)
+		}
+		toTime = sql.NullTime{Time: t, Valid: true}
+	}
+	feedFilter := optionalString(cmd.stringFlag("feed"))
+	folderFilter := optionalString(cmd.stringFlag("folder"))
+	tagFilter := optionalString(strings.ToLower(cmd.stringFlag("tag")))
+	searchFilter := optionalString(cmd.stringFlag("search"))
+	unreadOnly := cmd.boolFlag("unread")
+	starredOnly := cmd.boolFlag("starred")
+
+	var posts []database.GetPostsForUserRow
+	var err error
+	if after != "" {
+		afterPost, err := getPost(s, after)
 		if err != nil {
 			return err
 		}
+		newer, err := s.db.GetPostsForUserAfter(context.Background(), database.GetPostsForUserAfterParams{
+			UserID:      user.ID,
+			Feed:        feedFilter,
+			Folder:      folderFilter,
+			Tag:         tagFilter,
+			From:        fromTime,
+			Sort:        sort,
+			To:          toTime,
+			Search:      searchFilter,
+			UnreadOnly:  unreadOnly,
+			StarredOnly: starredOnly,
+			After:       afterPost.ID,
+			Limit:       int32(limit),
+		})
+		if err != nil {
+			return err
+		}
+		for _, post := range newer {
+			posts = append(posts, database.GetPostsForUserRow(post))
+		}
+		slices.Reverse(posts)
+	} else {
+		beforeID := uuid.NullUUID{}
+		if before != "" {
+			beforePost, err := getPost(s, before)
+			if err != nil {
+				return err
+			}
+			beforeID = uuid.NullUUID{UUID: beforePost.ID, Valid: true}
+		}
+		posts, err = s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID:      user.ID,
+			Feed:        feedFilter,
+			Folder:      folderFilter,
+			Tag:         tagFilter,
+			From:        fromTime,
+			Sort:        sort,
+			To:          toTime,
+			Search:      searchFilter,
+			UnreadOnly:  unreadOnly,
+			StarredOnly: starredOnly,
+			Before:      beforeID,
+			Limit:       int32(limit),
+			Offset:      int32((page - 1) * limit),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	l := output.NewList("id", "title", "url", "published_at", "fetched_at", "feed_id", "read", "starred")
+	for _, post := range posts {
+		l.Add(post.ID, post.Title, post.Url, post.PublishedAt, post.CreatedAt, post.FeedID, post.Read, post.Starred)
 	}
 
-	posts, err := s.db.GetPosts(context.Background(), int32(limit))
+	return s.render(l, func() {
+		for _, post := range posts {
+			fmt.Println("ID:", post.ID)
+			fmt.Println("Title:", htmltext.Clean(post.Title))
+			fmt.Println("Published at:", post.PublishedAt)
+			fmt.Println("URL:", htmltext.Clean(post.Url))
+			fmt.Println()
+		}
+
+		if len(posts) == 0 {
+			fmt.Println("No posts found")
+			return
+		}
+		if before != "" || after != "" || page > 1 {
+			fmt.Println("Newer posts: --after", posts[0].ID)
+		}
+		if len(posts) == limit {
+			fmt.Println("Older posts: --before", posts[len(posts)-1].ID)
+		}
+	})
+}
+
+func handlerTUI(s *state, cmd command, user database.User) error {
+	return tui.Run(context.Background(), s.db, user)
+}
+
+func handlerSearch(s *state, cmd command, user database.User) error {
+	all := cmd.boolFlag("all")
+	folder := cmd.stringFlag("folder")
+
+	limit := 10
+	if len(cmd.args) > 1 {
+		var err error
+		limit, err = strconv.Atoi(cmd.args[1])
+		if err != nil || limit < 1 {
+			return cmd.usageError("limit must be a positive number")
+		}
+	}
+	if all && folder != "" {
+		return cmd.usageError("--all and --folder can't be used together")
+	}
+
+	results, err := s.db.SearchPosts(context.Background(), database.SearchPostsParams{
+		Query:    cmd.args[0],
+		AllFeeds: all,
+		UserID:   user.ID,
+		Folder:   optionalString(folder),
+		Limit:    int32(limit),
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, post := range posts {
-		fmt.Println("Title:", post.Title)
-		fmt.Println("Published at:", post.PublishedAt)
-		fmt.Println("URL:", post.Url)
-		fmt.Println()
+	for i := range results {
+		// Snippets are cut from the title and the description with its tags
+		// removed, but entities still need decoding.
+		results[i].Snippet = html.UnescapeString(results[i].Snippet)
 	}
 
-	return nil
+	l := output.NewList("id", "title", "url", "published_at", "feed_name", "rank", "snippet")
+	for _, result := range results {
+		l.Add(result.ID, result.Title, result.Url, result.PublishedAt, result.FeedName, result.Rank, result.Snippet)
+	}
+
+	return s.render(l, func() {
+		if len(results) == 0 {
+			fmt.Println("No posts found")
+			return
+		}
+
+		for _, result := range results {
+			fmt.Println("ID:", result.ID)
+			fmt.Println("Title:", htmltext.Clean(result.Title))
+			fmt.Println("Feed:", htmltext.Clean(result.FeedName))
+			fmt.Println("Published at:", result.PublishedAt)
+			fmt.Println("URL:", htmltext.Clean(result.Url))
+			fmt.Println(htmltext.Clean(result.Snippet))
+			fmt.Println()
+		}
+	})
+}
+
+// getPost looks up a post by the ID given on the command line.
+func getPost(s *state, id string) (database.Post, error) {
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		return database.Post{}, fmt.Errorf("invalid post ID '%s'", id)
+	}
+	post, err := s.db.GetPost(context.Background(), postID)
+	if err == sql.ErrNoRows {
+		return post, fmt.Errorf("post '%s' not found", id)
+	}
+	return post, err
+}
+
+// optionalString turns an empty string into a NULL query parameter.
+func optionalString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
 }
 
 func middlewareLoggedIn(handler func(s *state, cmd command, user database.User) error) func(*state, command) error {
 	return func(s *state, cmd command) error {
+		if s.config.Current_user_name == "" {
+			return fmt.Errorf("you need to register or log in first")
+		}
 		user, err := s.db.GetUser(context.Background(), s.config.Current_user_name)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user '%s' doesn't exist, log in as another user", s.config.Current_user_name)
+		}
 		if err != nil {
 			return err
 		}
@@ -388,12 +1058,23 @@ func scrapeFeeds(s *state) {
 		log.Fatal(err)
 	}
 
+	if rssFeed.Channel.Link != feed.SiteUrl {
+		err = s.db.SetFeedSiteURL(context.Background(), database.SetFeedSiteURLParams{
+			ID:      feed.ID,
+			SiteUrl: rssFeed.Channel.Link,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	newPosts := []database.Post{}
 	for _, post := range rssFeed.Channel.Item {
 		pubDate, err := dateparse.ParseAny(post.PubDate)
 		if err != nil {
 			log.Fatal(err)
 		}
-		_, err = s.db.CreatePost(context.Background(), database.CreatePostParams{
+		createdPost, err := s.db.CreatePost(context.Background(), database.CreatePostParams{
 			ID:          uuid.New(),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
@@ -406,5 +1087,22 @@ func scrapeFeeds(s *state) {
 		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "unique") {
 			log.Fatal(err)
 		}
+		if err != nil {
+			continue
+		}
+		if feed.FullText {
+			createdPost = storeFullText(s, createdPost)
+		}
+		newPosts = append(newPosts, createdPost)
+	}
+
+	if err := rules.ApplyToNew(context.Background(), s.db, feed, newPosts); err != nil {
+		log.Fatal(err)
+	}
+	if err := alerts.Check(context.Background(), s.db, feed, newPosts, s.config.Smtp); err != nil {
+		log.Fatal(err)
+	}
+	if err := webhook.Queue(context.Background(), s.db, feed, newPosts); err != nil {
+		log.Fatal(err)
 	}
 }
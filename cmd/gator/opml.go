@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/opml"
+	"github.com/google/uuid"
+)
+
+func handlerImport(s *state, cmd command, user database.User) error {
+	if cmd.args[0] != "opml" {
+		return cmd.usageError("unknown import format '%s', only opml is supported", cmd.args[0])
+	}
+
+	f, err := os.Open(cmd.args[1])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	doc, err := opml.Parse(f)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := s.db.WithTx(tx)
+
+	created, followed, skipped, failed := 0, 0, 0, 0
+	for _, feed := range doc.Feeds() {
+		// Every entry gets its own savepoint so a failed one doesn't abort the
+		// transaction for the rest of the import.
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_feed"); err != nil {
+			return err
+		}
+
+		result, err := importFeed(ctx, qtx, user, feed)
+		if err != nil {
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_feed"); err != nil {
+				return err
+			}
+			failed++
+			fmt.Printf("failed   %s: %v\n", feed.XMLURL, err)
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_feed"); err != nil {
+			return err
+		}
+
+		switch result {
+		case "created":
+			created++
+			followed++
+		case "followed":
+			followed++
+		case "skipped":
+			skipped++
+		}
+		fmt.Printf("%-8s %s\n", result, feed.XMLURL)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCreated %d feeds, followed %d, skipped %d already followed, %d failed\n", created, followed, skipped, failed)
+	return nil
+}
+
+// importFeed adds a feed from an OPML document to the database if it's
+// missing, and follows it for the user inside the folder it was nested in.
+// It returns whether the feed was "created", only "followed", or "skipped"
+// because the user already follows it.
+func importFeed(ctx context.Context, q *database.Queries, user database.User, entry opml.Feed) (string, error) {
+	result := "followed"
+	feed, err := q.GetFeed(ctx, entry.XMLURL)
+	if err == sql.ErrNoRows {
+		name := entry.Title
+		if name == "" {
+			name = entry.XMLURL
+		}
+		feed, err = q.AddFeed(ctx, database.AddFeedParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      name,
+			Url:       entry.XMLURL,
+			UserID:    user.ID,
+		})
+		if err == nil && entry.HTMLURL != "" {
+			err = q.SetFeedSiteURL(ctx, database.SetFeedSiteURLParams{
+				ID:      feed.ID,
+				SiteUrl: entry.HTMLURL,
+			})
+		}
+		result = "created"
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = q.GetFeedFollow(ctx, database.GetFeedFollowParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err == nil {
+		return "skipped", nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	_, err = q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(entry.Folders) == 0 {
+		return result, nil
+	}
+
+	folderName := strings.Join(entry.Folders, "/")
+	folder, err := q.GetFolder(ctx, database.GetFolderParams{
+		UserID: user.ID,
+		Name:   folderName,
+	})
+	if err == sql.ErrNoRows {
+		folder, err = q.CreateFolder(ctx, database.CreateFolderParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      folderName,
+			UserID:    user.ID,
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = q.SetFeedFollowFolder(ctx, database.SetFeedFollowFolderParams{
+		UserID:   user.ID,
+		FeedID:   feed.ID,
+		FolderID: uuid.NullUUID{UUID: folder.ID, Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func handlerExport(s *state, cmd command) error {
+	if cmd.args[0] != "opml" {
+		return cmd.usageError("unknown export format '%s', only opml is supported", cmd.args[0])
+	}
+
+	userName := cmd.stringFlag("user")
+	if userName == "" {
+		userName = s.config.Current_user_name
+	}
+	user, err := s.db.GetUser(context.Background(), userName)
+	if err != nil {
+		return fmt.Errorf("user '%s' not found", userName)
+	}
+
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return err
+	}
+
+	doc := opml.New(fmt.Sprintf("Feeds followed by %s in Gator", user.Name))
+	for _, feedFollow := range feedFollows {
+		folders := []string{}
+		if feedFollow.FolderName.Valid {
+			folders = strings.Split(feedFollow.FolderName.String, "/")
+		}
+		doc.AddFeed(opml.Feed{
+			Title:   feedFollow.FeedName,
+			XMLURL:  feedFollow.FeedUrl,
+			HTMLURL: feedFollow.FeedSiteUrl,
+			Folders: folders,
+		})
+	}
+
+	var w io.Writer = os.Stdout
+	if len(cmd.args) > 1 {
+		f, err := os.Create(cmd.args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return doc.Write(w)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/planet"
+	"github.com/R0Xps/gatorcli/internal/publish"
+	"github.com/google/uuid"
+)
+
+const planetPageSize = 100
+
+func handlerPlanet(s *state, cmd command) error {
+	days := cmd.intFlag("days")
+	if days < 1 {
+		return cmd.usageError("days must be a positive number")
+	}
+	out := cmd.stringFlag("out")
+	if out == "" {
+		return cmd.usageError("--out can't be empty")
+	}
+	folder := cmd.stringFlag("folder")
+
+	userName := cmd.stringFlag("user")
+	if userName == "" {
+		userName = s.config.Current_user_name
+	}
+	user, err := s.db.GetUser(context.Background(), userName)
+	if err != nil {
+		return fmt.Errorf("user '%s' not found", userName)
+	}
+	if folder != "" {
+		_, err := s.db.GetFolder(context.Background(), database.GetFolderParams{UserID: user.ID, Name: folder})
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("folder '%s' not found", folder)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	feedFollows, err := s.db.GetFeedFollowsForUser(context.Background(), user.Name)
+	if err != nil {
+		return err
+	}
+	chosen := map[string]bool{}
+	for _, arg := range cmd.args {
+		chosen[arg] = true
+	}
+	sources := map[uuid.UUID]database.GetFeedFollowsForUserRow{}
+	feeds := []*planet.Feed{}
+	for _, ff := range feedFollows {
+		if folder != "" && ff.FolderName.String != folder {
+			continue
+		}
+		if len(chosen) > 0 && !chosen[ff.FeedUrl] && !chosen[ff.FeedName] {
+			continue
+		}
+		delete(chosen, ff.FeedUrl)
+		delete(chosen, ff.FeedName)
+		sources[ff.FeedID] = ff
+		feeds = append(feeds, &planet.Feed{
+			ID:      ff.FeedID,
+			Name:    ff.FeedName,
+			URL:     ff.FeedUrl,
+			SiteURL: ff.FeedSiteUrl,
+		})
+	}
+	for _, arg := range cmd.args {
+		if chosen[arg] {
+			return fmt.Errorf("feed '%s' isn't followed by %s", arg, user.Name)
+		}
+	}
+	if len(feeds) == 0 {
+		return fmt.Errorf("no feeds to publish, follow some first")
+	}
+
+	entries := []publish.Entry{}
+	before := uuid.NullUUID{}
+	for {
+		posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID: user.ID,
+			Folder: sql.NullString{String: folder, Valid: folder != ""},
+			From:   sql.NullTime{Time: time.Now().AddDate(0, 0, -days), Valid: true},
+			Sort:   "published",
+			Before: before,
+			Limit:  planetPageSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, p := range posts {
+			source, ok := sources[p.FeedID]
+			if !ok {
+				continue
+			}
+			entry, err := publish.NewEntry(p, source)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		if len(posts) < planetPageSize {
+			break
+		}
+		before = uuid.NullUUID{UUID: posts[len(posts)-1].ID, Valid: true}
+	}
+
+	title := cmd.stringFlag("title")
+	if title == "" {
+		title = fmt.Sprintf("Planet %s", user.Name)
+	}
+	site := planet.New(title, cmd.stringFlag("url"), feeds, entries)
+	if err := site.Build(out, cmd.stringFlag("templates")); err != nil {
+		return err
+	}
+	fmt.Printf("Published %d posts from %d feeds to %s\n", len(entries), len(feeds), out)
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/publish"
+)
+
+func handlerPublish(s *state, cmd command) error {
+	format, err := publish.ParseFormat(cmd.stringFlag("format"))
+	if err != nil {
+		return cmd.usageError("%v", err)
+	}
+	limit := cmd.intFlag("limit")
+	if limit < 1 {
+		return cmd.usageError("limit must be a positive number")
+	}
+
+	userName := cmd.stringFlag("user")
+	if userName == "" {
+		userName = s.config.Current_user_name
+	}
+	user, err := s.db.GetUser(context.Background(), userName)
+	if err != nil {
+		return fmt.Errorf("user '%s' not found", userName)
+	}
+
+	feed, err := publish.Timeline(context.Background(), s.db, user, cmd.stringFlag("folder"), limit)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if len(cmd.args) > 0 {
+		f, err := os.Create(cmd.args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return publish.Write(w, feed, format)
+}
+
+func handlerFeedToken(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		saved, err := s.db.GetFeedToken(context.Background(), user.ID)
+		if err == sql.ErrNoRows {
+			fmt.Printf("%s has no feed token, make one with 'gator feedtoken new'\n", user.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		printFeedURL(user, saved.Token)
+		return nil
+	}
+
+	switch cmd.args[0] {
+	case "new":
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		token := hex.EncodeToString(b)
+		err := s.db.SetFeedToken(context.Background(), database.SetFeedTokenParams{
+			UserID:    user.ID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Token:     token,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("New feed token for %s, URLs with the old one stop working\n", user.Name)
+		printFeedURL(user, token)
+		return nil
+	case "off":
+		n, err := s.db.DeleteFeedToken(context.Background(), user.ID)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			fmt.Printf("%s had no feed token\n", user.Name)
+			return nil
+		}
+		fmt.Printf("The feed token of %s has been removed, URLs with it stop working\n", user.Name)
+		return nil
+	default:
+		return cmd.usageError("expected 'new' or 'off', got '%s'", cmd.args[0])
+	}
+}
+
+func printFeedURL(user database.User, token string) {
+	fmt.Println("Token:", token)
+	fmt.Printf("Feed: /users/%s/feed.xml?token=%s\n", url.PathEscape(user.Name), token)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/archive"
+	"github.com/R0Xps/gatorcli/internal/browser"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"golang.org/x/term"
+)
+
+// maxShowWidth keeps the lines of posts shown in wide terminals readable.
+const maxShowWidth = 100
+
+func handlerOpen(s *state, cmd command, user database.User) error {
+	post, err := getPost(s, cmd.args[0])
+	if err != nil {
+		return err
+	}
+	if err := browser.Open(post.Url); err != nil {
+		return err
+	}
+	return markRead(s, user, post)
+}
+
+func handlerShow(s *state, cmd command, user database.User) error {
+	width := cmd.intFlag("width")
+	if width < 0 {
+		return cmd.usageError("width must be a positive number")
+	}
+	if width == 0 {
+		width = 80
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			width = min(w, maxShowWidth)
+		}
+	}
+
+	post, err := getPost(s, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	// Show the archived copy of the post, if there's one.
+	archived, err := s.db.GetPostArchive(context.Background(), database.GetPostArchiveParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		data, err := os.ReadFile(archived.Path)
+		if err == nil {
+			if archive.Format(archived.Format) == archive.Markdown {
+				fmt.Print(htmltext.Clean(string(data)))
+			} else {
+				fmt.Println(htmltext.Render(string(data), post.Url, width))
+			}
+			fmt.Printf("\nArchived copy: %s\n", archived.Path)
+			return markRead(s, user, post)
+		}
+		fmt.Fprintf(os.Stderr, "gator: couldn't read the archived copy, showing the post instead: %v\n", err)
+	}
+
+	// Titles and URLs come from feeds, which could hide escape sequences in
+	// them, like the text htmltext renders.
+	fmt.Println(strings.Join(htmltext.Wrap(htmltext.Clean(post.Title), width), "\n"))
+	fmt.Println(post.PublishedAt.Format("January 2, 2006 15:04"))
+	fmt.Println(htmltext.Clean(post.Url))
+	if body := htmltext.Render(postBody(post.Description, post.Content), post.Url, width); body != "" {
+		fmt.Println()
+		fmt.Println(body)
+	}
+	return markRead(s, user, post)
+}
+
+// postBody is the text to read of a post: the full article if it was
+// extracted, otherwise the description from the feed.
+func postBody(description, content string) string {
+	if content != "" {
+		return content
+	}
+	return description
+}
+
+func markRead(s *state, user database.User, post database.Post) error {
+	return s.db.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		CreatedAt: time.Now(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+	})
+}
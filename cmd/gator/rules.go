@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/R0Xps/gatorcli/internal/rules"
+	"github.com/google/uuid"
+)
+
+// rulePageSize is the number of posts matched against rules at once when
+// testing or applying them to existing posts.
+const rulePageSize = 100
+
+func handlerRule(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return listRules(s, user)
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "list":
+		return listRules(s, user)
+	case "add":
+		if len(args) != 0 {
+			return cmd.usageError("'rule add' expects no arguments, only flags")
+		}
+		if cmd.stringFlag("action") == "" {
+			return cmd.usageError("'rule add' needs an --action")
+		}
+		r, err := ruleFromFlags(s, cmd, user)
+		if err != nil {
+			return err
+		}
+		saved, err := s.db.CreateRule(context.Background(), database.CreateRuleParams{
+			ID:           r.ID,
+			CreatedAt:    r.CreatedAt,
+			UpdatedAt:    r.UpdatedAt,
+			UserID:       r.UserID,
+			FeedID:       r.FeedID,
+			TitleRegex:   r.TitleRegex,
+			ContentRegex: r.ContentRegex,
+			Action:       r.Action,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rule %s has been added, it applies to new posts\n", saved.ID)
+		fmt.Printf("Apply it to the posts you already have with 'gator rule apply %s'\n", saved.ID)
+	case "remove":
+		if len(args) != 1 {
+			return cmd.usageError("'rule remove' expects 1 argument (id)")
+		}
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return cmd.usageError("invalid rule ID '%s'", args[0])
+		}
+		removed, err := s.db.DeleteRule(context.Background(), database.DeleteRuleParams{ID: id, UserID: user.ID})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("rule '%s' not found", args[0])
+		}
+		fmt.Printf("Rule %s has been removed\n", id)
+	case "test":
+		if len(args) > 1 {
+			return cmd.usageError("'rule test' expects at most 1 argument (id)")
+		}
+		var r rules.Rule
+		var err error
+		if len(args) == 1 {
+			r, err = getRule(s, cmd, user, args[0])
+		} else {
+			r, err = ruleFromFlags(s, cmd, user)
+		}
+		if err != nil {
+			return err
+		}
+		return testRule(s, user, r)
+	case "apply":
+		if len(args) > 1 {
+			return cmd.usageError("'rule apply' expects at most 1 argument (id)")
+		}
+		var toApply []rules.Rule
+		if len(args) == 1 {
+			r, err := getRule(s, cmd, user, args[0])
+			if err != nil {
+				return err
+			}
+			toApply = append(toApply, r)
+		} else {
+			saved, err := s.db.GetRulesForUser(context.Background(), user.ID)
+			if err != nil {
+				return err
+			}
+			for _, r := range saved {
+				rule, err := rules.Compile(ruleFromRow(r))
+				if err != nil {
+					return fmt.Errorf("rule %s: %w", r.ID, err)
+				}
+				toApply = append(toApply, rule)
+			}
+		}
+		matched := map[uuid.UUID]bool{}
+		err := matchPosts(s, user, toApply, func(p database.GetPostsForUserRow, r rules.Rule) error {
+			matched[p.ID] = true
+			return r.Apply(context.Background(), s.db, p.ID)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d rules to %d matching posts\n", len(toApply), len(matched))
+	case "unhide":
+		if len(args) != 1 {
+			return cmd.usageError("'rule unhide' expects 1 argument (post_id)")
+		}
+		post, err := getPost(s, args[0])
+		if err != nil {
+			return err
+		}
+		unhidden, err := s.db.UnhidePost(context.Background(), database.UnhidePostParams{UserID: user.ID, PostID: post.ID})
+		if err != nil {
+			return err
+		}
+		if unhidden == 0 {
+			return fmt.Errorf("post '%s' isn't hidden", args[0])
+		}
+		fmt.Printf("Post '%s' is no longer hidden\n", htmltext.Clean(post.Title))
+	default:
+		return cmd.usageError("unknown rule subcommand '%s', expected one of: list, add, remove, test, apply, unhide", cmd.args[0])
+	}
+	return nil
+}
+
+func listRules(s *state, user database.User) error {
+	saved, err := s.db.GetRulesForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	l := output.NewList("id", "feed", "title_regex", "content_regex", "action", "created_at")
+	for _, r := range saved {
+		l.Add(r.ID, r.FeedUrl.String, r.TitleRegex, r.ContentRegex, r.Action, r.CreatedAt)
+	}
+
+	return s.render(l, func() {
+		if len(saved) == 0 {
+			fmt.Println("No rules yet, add one with 'gator rule add --title-regex <regex> --action <action>'")
+		}
+		for _, r := range saved {
+			feed := "all followed feeds"
+			if r.FeedUrl.Valid {
+				feed = r.FeedUrl.String
+			}
+			fmt.Printf("%s %s\n", r.ID, r.Action)
+			fmt.Printf("  for %s\n", feed)
+			if r.TitleRegex != "" {
+				fmt.Printf("  title matches %s\n", r.TitleRegex)
+			}
+			if r.ContentRegex != "" {
+				fmt.Printf("  content matches %s\n", r.ContentRegex)
+			}
+		}
+	})
+}
+
+// testRule lists the posts a rule matches, without applying it.
+func testRule(s *state, user database.User, r rules.Rule) error {
+	var matches []database.GetPostsForUserRow
+	err := matchPosts(s, user, []rules.Rule{r}, func(p database.GetPostsForUserRow, _ rules.Rule) error {
+		matches = append(matches, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	l := output.NewList("id", "title", "url", "published_at", "feed_id")
+	for _, p := range matches {
+		l.Add(p.ID, p.Title, p.Url, p.PublishedAt, p.FeedID)
+	}
+	return s.render(l, func() {
+		for _, p := range matches {
+			fmt.Println("ID:", p.ID)
+			fmt.Println("Title:", htmltext.Clean(p.Title))
+			fmt.Println("Published at:", p.PublishedAt)
+			fmt.Println("URL:", htmltext.Clean(p.Url))
+			fmt.Println()
+		}
+		switch len(matches) {
+		case 0:
+			fmt.Println("The rule matches no posts")
+		case 1:
+			fmt.Println("The rule matches 1 post")
+		default:
+			fmt.Printf("The rule matches %d posts\n", len(matches))
+		}
+	})
+}
+
+// matchPosts calls fn for each of the user's posts and each rule it matches,
+// newest posts first. Hidden posts are skipped.
+func matchPosts(s *state, user database.User, toMatch []rules.Rule, fn func(database.GetPostsForUserRow, rules.Rule) error) error {
+	if len(toMatch) == 0 {
+		return nil
+	}
+	before := uuid.NullUUID{}
+	for {
+		posts, err := s.db.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+			UserID: user.ID,
+			Sort:   "published",
+			Before: before,
+			Limit:  rulePageSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, p := range posts {
+			for _, r := range toMatch {
+				if !r.Matches(p.FeedID, p.Title, p.Description, p.Content) {
+					continue
+				}
+				if err := fn(p, r); err != nil {
+					return err
+				}
+			}
+		}
+		if len(posts) < rulePageSize {
+			return nil
+		}
+		before = uuid.NullUUID{UUID: posts[len(posts)-1].ID, Valid: true}
+	}
+}
+
+// ruleFromFlags makes a new rule from the --feed, --title-regex,
+// --content-regex and --action flags.
+func ruleFromFlags(s *state, cmd command, user database.User) (rules.Rule, error) {
+	r := database.Rule{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		UserID:       user.ID,
+		TitleRegex:   cmd.stringFlag("title-regex"),
+		ContentRegex: cmd.stringFlag("content-regex"),
+	}
+	if r.TitleRegex == "" && r.ContentRegex == "" {
+		return rules.Rule{}, cmd.usageError("a rule needs a --title-regex or a --content-regex")
+	}
+	if action := cmd.stringFlag("action"); action != "" {
+		var err error
+		if r.Action, err = rules.ParseAction(action); err != nil {
+			return rules.Rule{}, cmd.usageError("%v", err)
+		}
+	}
+	if feedURL := cmd.stringFlag("feed"); feedURL != "" {
+		feed, err := s.db.GetFeed(context.Background(), feedURL)
+		if err == sql.ErrNoRows {
+			return rules.Rule{}, fmt.Errorf("feed '%s' not found", feedURL)
+		}
+		if err != nil {
+			return rules.Rule{}, err
+		}
+		r.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	rule, err := rules.Compile(r)
+	if err != nil {
+		return rule, cmd.usageError("%v", err)
+	}
+	return rule, nil
+}
+
+func ruleFromRow(r database.GetRulesForUserRow) database.Rule {
+	return database.Rule{
+		ID:           r.ID,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+		UserID:       r.UserID,
+		FeedID:       r.FeedID,
+		TitleRegex:   r.TitleRegex,
+		ContentRegex: r.ContentRegex,
+		Action:       r.Action,
+	}
+}
+
+func getRule(s *state, cmd command, user database.User, arg string) (rules.Rule, error) {
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return rules.Rule{}, cmd.usageError("invalid rule ID '%s'", arg)
+	}
+	r, err := s.db.GetRule(context.Background(), database.GetRuleParams{ID: id, UserID: user.ID})
+	if err == sql.ErrNoRows {
+		return rules.Rule{}, fmt.Errorf("rule '%s' not found", arg)
+	}
+	if err != nil {
+		return rules.Rule{}, err
+	}
+	return rules.Compile(r)
+}
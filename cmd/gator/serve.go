@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/api"
+	"github.com/R0Xps/gatorcli/internal/fever"
+	"github.com/R0Xps/gatorcli/internal/greader"
+	"github.com/R0Xps/gatorcli/internal/publish"
+	"github.com/R0Xps/gatorcli/internal/web"
+)
+
+func handlerServe(s *state, cmd command) error {
+	addr := cmd.stringFlag("addr")
+	token := cmd.stringFlag("token")
+	if token == "" {
+		token = os.Getenv("GATOR_API_TOKEN")
+	}
+	switch {
+	case addr == "" && token == "":
+		addr = "127.0.0.1:8080"
+	case addr == "":
+		addr = ":8080"
+	case token == "" && !isLoopback(addr):
+		return fmt.Errorf("refusing to serve on %s without a token, anyone who can reach it could use it as any user; set one with --token or $GATOR_API_TOKEN, or listen on 127.0.0.1", addr)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", api.New(s.conn, s.db, token))
+	mux.Handle("/fever/", fever.New(s.db))
+	mux.Handle("/greader/", greader.New(s.db))
+	mux.Handle("/users/", publish.NewHandler(s.db, token))
+	mux.Handle("/", web.New(s.db, token))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           logRequests(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving the web reader and the API on %s\n", addr)
+	if token == "" {
+		fmt.Println("No token set, so only this machine can reach the server")
+	}
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// isLoopback reports whether addr only accepts connections from this machine.
+// An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %d %v", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
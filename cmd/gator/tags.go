@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/google/uuid"
+)
+
+func handlerTag(s *state, cmd command, user database.User) error {
+	post, err := getPost(s, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, name := range cmd.args[1:] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		tag, err := s.db.CreateTag(context.Background(), database.CreateTagParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      name,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.db.AddPostTag(context.Background(), database.AddPostTagParams{
+			CreatedAt: time.Now(),
+			PostID:    post.ID,
+			TagID:     tag.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return printPostTags(s, user, post)
+}
+
+func handlerUntag(s *state, cmd command, user database.User) error {
+	post, err := getPost(s, cmd.args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, name := range cmd.args[1:] {
+		_, err := s.db.RemovePostTag(context.Background(), database.RemovePostTagParams{
+			PostID: post.ID,
+			UserID: user.ID,
+			Name:   strings.ToLower(strings.TrimSpace(name)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.db.DeleteUnusedTags(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return printPostTags(s, user, post)
+}
+
+func handlerTags(s *state, cmd command, user database.User) error {
+	prefix := ""
+	if len(cmd.args) > 0 {
+		prefix = strings.ToLower(cmd.args[0])
+	}
+
+	tags, err := s.db.GetTagsForUser(context.Background(), database.GetTagsForUserParams{
+		UserID: user.ID,
+		Prefix: prefix,
+	})
+	if err != nil {
+		return err
+	}
+
+	l := output.NewList("name", "post_count")
+	for _, tag := range tags {
+		l.Add(tag.Name, tag.PostCount)
+	}
+
+	return s.render(l, func() {
+		for _, tag := range tags {
+			fmt.Printf("%s (%d)\n", tag.Name, tag.PostCount)
+		}
+	})
+}
+
+func printPostTags(s *state, user database.User, post database.Post) error {
+	tags, err := s.db.GetTagsForPost(context.Background(), database.GetTagsForPostParams{
+		PostID: post.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Title:", htmltext.Clean(post.Title))
+	fmt.Println("Tags:", strings.Join(tags, ", "))
+	return nil
+}
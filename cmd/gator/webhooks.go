@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/output"
+	"github.com/R0Xps/gatorcli/internal/webhook"
+	"github.com/google/uuid"
+)
+
+// webhookLogSize is the number of deliveries 'webhooks log' shows.
+const webhookLogSize = 20
+
+func handlerWebhooks(s *state, cmd command, user database.User) error {
+	if len(cmd.args) == 0 {
+		return listWebhooks(s, user)
+	}
+
+	args := cmd.args[1:]
+	switch cmd.args[0] {
+	case "list":
+		return listWebhooks(s, user)
+	case "add":
+		if len(args) < 1 || len(args) > 2 {
+			return cmd.usageError("'webhooks add' expects 1 or 2 arguments (url, [feed_url])")
+		}
+		u, err := url.Parse(args[0])
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cmd.usageError("invalid webhook URL '%s'", args[0])
+		}
+		feedID := uuid.NullUUID{}
+		if len(args) > 1 {
+			feed, err := s.db.GetFeed(context.Background(), args[1])
+			if err != nil {
+				return fmt.Errorf("feed '%s' not found", args[1])
+			}
+			feedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+		}
+		w, err := s.db.CreateWebhook(context.Background(), database.CreateWebhookParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			UserID:    user.ID,
+			FeedID:    feedID,
+			Url:       args[0],
+			Secret:    webhook.NewSecret(),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Webhook %s has been added\n", w.ID)
+		fmt.Printf("Its requests are signed with this secret in the X-Gator-Signature header:\n%s\n", w.Secret)
+	case "remove":
+		if len(args) != 1 {
+			return cmd.usageError("'webhooks remove' expects 1 argument (id)")
+		}
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return cmd.usageError("invalid webhook ID '%s'", args[0])
+		}
+		removed, err := s.db.DeleteWebhook(context.Background(), database.DeleteWebhookParams{ID: id, UserID: user.ID})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("webhook '%s' not found", args[0])
+		}
+		fmt.Printf("Webhook %s has been removed\n", id)
+	case "test":
+		if len(args) != 1 {
+			return cmd.usageError("'webhooks test' expects 1 argument (id)")
+		}
+		w, err := getWebhook(s, cmd, user, args[0])
+		if err != nil {
+			return err
+		}
+		delivery, err := webhook.Test(context.Background(), s.db, w)
+		if err != nil {
+			return err
+		}
+		if !delivery.DeliveredAt.Valid {
+			return fmt.Errorf("couldn't deliver the test to %s: %s", w.Url, delivery.LastError)
+		}
+		fmt.Printf("Delivered a test to %s (status %d)\n", w.Url, delivery.StatusCode.Int32)
+	case "log":
+		if len(args) != 1 {
+			return cmd.usageError("'webhooks log' expects 1 argument (id)")
+		}
+		w, err := getWebhook(s, cmd, user, args[0])
+		if err != nil {
+			return err
+		}
+		return listWebhookDeliveries(s, w)
+	default:
+		return cmd.usageError("unknown webhooks subcommand '%s', expected one of: list, add, remove, test, log", cmd.args[0])
+	}
+	return nil
+}
+
+func listWebhooks(s *state, user database.User) error {
+	webhooks, err := s.db.GetWebhooksForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	l := output.NewList("id", "url", "feed", "last_status", "created_at")
+	for _, w := range webhooks {
+		var lastStatus any
+		if w.LastStatusCode != 0 {
+			lastStatus = w.LastStatusCode
+		}
+		l.Add(w.ID, w.Url, w.FeedUrl.String, lastStatus, w.CreatedAt)
+	}
+
+	return s.render(l, func() {
+		if len(webhooks) == 0 {
+			fmt.Println("No webhooks yet, add one with 'gator webhooks add <url>'")
+		}
+		for _, w := range webhooks {
+			feed := "all followed feeds"
+			if w.FeedUrl.Valid {
+				feed = w.FeedUrl.String
+			}
+			status := "no deliveries yet"
+			if w.LastStatusCode != 0 {
+				status = "last status " + strconv.Itoa(int(w.LastStatusCode))
+			}
+			fmt.Printf("%s %s\n", w.ID, w.Url)
+			fmt.Printf("  for %s, %s\n", feed, status)
+		}
+	})
+}
+
+func listWebhookDeliveries(s *state, w database.Webhook) error {
+	deliveries, err := s.db.GetWebhookDeliveries(context.Background(), database.GetWebhookDeliveriesParams{
+		WebhookID: w.ID,
+		Limit:     webhookLogSize,
+	})
+	if err != nil {
+		return err
+	}
+	l := output.NewList("id", "event", "status", "attempts", "error", "created_at", "delivered_at", "next_attempt_at")
+	for _, d := range deliveries {
+		l.Add(d.ID, d.Event, deliveryStatus(d), d.Attempts, d.LastError, d.CreatedAt, nullTime(d.DeliveredAt), nullTime(d.NextAttemptAt))
+	}
+
+	return s.render(l, func() {
+		if len(deliveries) == 0 {
+			fmt.Println("No deliveries yet")
+		}
+		for _, d := range deliveries {
+			fmt.Printf("%s %-13s %-9s %s\n", d.CreatedAt.Format(time.DateTime), d.Event, deliveryStatus(d), d.ID)
+			if d.LastError != "" {
+				fmt.Printf("  attempt %d: %s\n", d.Attempts, d.LastError)
+			}
+			if d.NextAttemptAt.Valid {
+				fmt.Printf("  next attempt at %s\n", d.NextAttemptAt.Time.Format(time.DateTime))
+			}
+		}
+	})
+}
+
+// deliveryStatus describes where a delivery is at: delivered, pending (not
+// tried yet or to be retried) or failed (given up on).
+func deliveryStatus(d database.WebhookDelivery) string {
+	switch {
+	case d.DeliveredAt.Valid:
+		return "delivered"
+	case d.NextAttemptAt.Valid:
+		return "pending"
+	}
+	return "failed"
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func getWebhook(s *state, cmd command, user database.User, arg string) (database.Webhook, error) {
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return database.Webhook{}, cmd.usageError("invalid webhook ID '%s'", arg)
+	}
+	w, err := s.db.GetWebhook(context.Background(), database.GetWebhookParams{ID: id, UserID: user.ID})
+	if err == sql.ErrNoRows {
+		return w, fmt.Errorf("webhook '%s' not found", arg)
+	}
+	return w, err
+}
@@ -3,7 +3,11 @@ module github.com/R0Xps/gatorcli
 go 1.24.5
 
 require (
-	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de // indirect
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
+	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de
+	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
+	golang.org/x/net v0.38.0
+	golang.org/x/term v0.30.0
 )
+
+require golang.org/x/sys v0.31.0 // indirect
@@ -0,0 +1,222 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/R0Xps/gatorcli/internal/config"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/digest"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/R0Xps/gatorcli/internal/rules"
+	"github.com/R0Xps/gatorcli/internal/webhook"
+	"github.com/google/uuid"
+)
+
+// snippetContext is about how many characters of text snippets show on each
+// side of a match.
+const snippetContext = 80
+
+// Matcher finds a watch word in posts.
+type Matcher struct {
+	database.WatchWord
+	re *regexp.Regexp
+}
+
+// Alert is a watch word found in a new post, as it's emailed.
+type Alert struct {
+	Word      string
+	Snippet   string
+	PostTitle string
+	PostURL   string
+	FeedName  string
+}
+
+// Compile compiles a watch word. Words that aren't regexes match without
+// regard to case, and as whole words if they start or end with a letter or
+// a digit, so that "go" doesn't match "good".
+func Compile(w database.WatchWord) (Matcher, error) {
+	pattern := w.Word
+	if !w.Regex {
+		pattern = wordPattern(w.Word)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Matcher{}, fmt.Errorf("invalid regex '%s': %w", w.Word, err)
+	}
+	if re.MatchString("") {
+		return Matcher{}, fmt.Errorf("'%s' matches empty text", w.Word)
+	}
+	return Matcher{WatchWord: w, re: re}, nil
+}
+
+func wordPattern(word string) string {
+	pattern := regexp.QuoteMeta(word)
+	if first, _ := utf8.DecodeRuneInString(word); isWordRune(first) {
+		pattern = `\b` + pattern
+	}
+	if last, _ := utf8.DecodeLastRuneInString(word); isWordRune(last) {
+		pattern += `\b`
+	}
+	return "(?i)" + pattern
+}
+
+// isWordRune reports whether r is a character \b considers part of a word.
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+// Match looks for the watch word in a post's title, then in the text of its
+// description and content. It returns a snippet of where it was found, with
+// the matches wrapped in "**".
+func (m Matcher) Match(title, description, content string) (string, bool) {
+	if m.re.MatchString(title) {
+		return m.highlight(title), true
+	}
+	for _, s := range []string{description, content} {
+		text := htmltext.Summary(s, math.MaxInt)
+		if loc := m.re.FindStringIndex(text); loc != nil {
+			return m.highlight(excerpt(text, loc[0], loc[1])), true
+		}
+	}
+	return "", false
+}
+
+func (m Matcher) highlight(s string) string {
+	return m.re.ReplaceAllStringFunc(s, func(match string) string {
+		return "**" + match + "**"
+	})
+}
+
+// excerpt returns the text around text[start:end], cut at word boundaries.
+func excerpt(text string, start, end int) string {
+	prefix, suffix := "", ""
+	if from := start - snippetContext; from > 0 {
+		if i := strings.IndexByte(text[from:start], ' '); i >= 0 {
+			start = from + i + 1
+		}
+		prefix = "… "
+	} else {
+		start = 0
+	}
+	if to := end + snippetContext; to < len(text) {
+		if i := strings.LastIndexByte(text[end:to], ' '); i >= 0 {
+			end += i
+		}
+		suffix = " …"
+	} else {
+		end = len(text)
+	}
+	return prefix + text[start:end] + suffix
+}
+
+// Check looks for the watch words of the users following feed in its new
+// posts, except the ones they've hidden, and saves an alert for each one
+// found. Alerts are also sent to the user's webhooks or emailed to the user's
+// address through the mail server in smtp, if the watch word asks for it.
+// Emails that can't be sent are only logged.
+func Check(ctx context.Context, q *database.Queries, feed database.Feed, posts []database.Post, smtp *config.SMTP) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	words, err := q.GetWatchWordsForFeed(ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+	emails := map[uuid.UUID][]Alert{}
+	hiddenByUser := map[uuid.UUID]map[uuid.UUID]bool{}
+	for _, w := range words {
+		m, err := Compile(w)
+		if err != nil {
+			continue
+		}
+		hidden, ok := hiddenByUser[w.UserID]
+		if !ok {
+			if hidden, err = rules.Hidden(ctx, q, w.UserID, posts); err != nil {
+				return err
+			}
+			hiddenByUser[w.UserID] = hidden
+		}
+		for _, p := range posts {
+			if hidden[p.ID] {
+				continue
+			}
+			snippet, ok := m.Match(p.Title, p.Description, p.Content)
+			if !ok {
+				continue
+			}
+			id := uuid.New()
+			created, err := q.CreateAlert(ctx, database.CreateAlertParams{
+				ID:          id,
+				CreatedAt:   time.Now(),
+				WatchWordID: w.ID,
+				PostID:      p.ID,
+				Snippet:     snippet,
+			})
+			if err != nil {
+				return err
+			}
+			if created == 0 {
+				continue
+			}
+			if w.NotifyWebhooks {
+				alert := webhook.Alert{ID: id, Word: w.Word, Snippet: snippet}
+				if err := webhook.QueueAlert(ctx, q, w.UserID, feed, p, alert); err != nil {
+					return err
+				}
+			}
+			if w.NotifyEmail {
+				emails[w.UserID] = append(emails[w.UserID], Alert{
+					Word:      w.Word,
+					Snippet:   snippet,
+					PostTitle: p.Title,
+					PostURL:   p.Url,
+					FeedName:  feed.Name,
+				})
+			}
+		}
+	}
+	for userID, found := range emails {
+		user, err := q.GetUserById(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := Email(smtp, user.Email, found); err != nil {
+			log.Printf("Couldn't email alerts to %s: %v", user.Name, err)
+		}
+	}
+	return nil
+}
+
+// Email sends alerts in one email to the address to.
+func Email(smtp *config.SMTP, to string, found []Alert) error {
+	if smtp == nil || smtp.Host == "" || smtp.From == "" {
+		return fmt.Errorf("no mail server set, add smtp with a host and a from address to the config file")
+	}
+	if to == "" {
+		return fmt.Errorf("no email address set, set one with 'gator alerts email <address>'")
+	}
+	subject := fmt.Sprintf("Gator alert: %s in %s", found[0].Word, found[0].PostTitle)
+	if len(found) > 1 {
+		subject = fmt.Sprintf("Gator alerts: %d new", len(found))
+	}
+	var text strings.Builder
+	for _, a := range found {
+		fmt.Fprintf(&text, "%s in %s\n", a.Word, a.FeedName)
+		fmt.Fprintf(&text, "%s\n%s\n%s\n\n", a.PostTitle, a.PostURL, a.Snippet)
+	}
+	text.WriteString("--\nSent by Gator.\n")
+
+	msg, err := digest.TextMessage(smtp.From, to, subject, []byte(text.String()))
+	if err != nil {
+		return err
+	}
+	return digest.Send(*smtp, to, msg)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxBodySize limits the size of request bodies.
+const maxBodySize = 1 << 20
+
+// Server serves version 1 of the REST API under /v1/. All responses are JSON:
+// lists are wrapped in an object with a "data" field, and errors are objects
+// with an "error" field.
+type Server struct {
+	conn  *sql.DB
+	q     *database.Queries
+	token string
+	mux   *http.ServeMux
+}
+
+// New returns an API server using q, which runs its queries on conn. If
+// token isn't empty, requests must carry it in an "Authorization: Bearer"
+// header.
+func New(conn *sql.DB, q *database.Queries, token string) *Server {
+	s := &Server{conn: conn, q: q, token: token, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/users", s.listUsers)
+	s.mux.HandleFunc("POST /v1/users", s.createUser)
+	s.mux.HandleFunc("GET /v1/users/{name}", s.getUser)
+	s.mux.HandleFunc("GET /v1/users/{name}/follows", s.listFollows)
+	s.mux.HandleFunc("POST /v1/users/{name}/follows", s.createFollow)
+	s.mux.HandleFunc("DELETE /v1/users/{name}/follows/{feed_id}", s.deleteFollow)
+	s.mux.HandleFunc("GET /v1/users/{name}/posts", s.listPosts)
+	s.mux.HandleFunc("GET /v1/users/{name}/posts/{id}", s.getPost)
+	s.mux.HandleFunc("PUT /v1/users/{name}/posts/{id}/read", s.setPostState)
+	s.mux.HandleFunc("DELETE /v1/users/{name}/posts/{id}/read", s.setPostState)
+	s.mux.HandleFunc("PUT /v1/users/{name}/posts/{id}/star", s.setPostState)
+	s.mux.HandleFunc("DELETE /v1/users/{name}/posts/{id}/star", s.setPostState)
+	s.mux.HandleFunc("GET /v1/feeds", s.listFeeds)
+	s.mux.HandleFunc("POST /v1/feeds", s.createFeed)
+	s.mux.HandleFunc("GET /v1/feeds/{id}", s.getFeed)
+	s.mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gator"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+type list[T any] struct {
+	Data []T `json:"data"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// writeDBError reports an error from the database: missing rows as not found,
+// unique constraint violations as conflicts, and anything else as an
+// internal error, without details.
+func writeDBError(w http.ResponseWriter, err error, what string) {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, "%s not found", what)
+	case errors.As(err, &pqErr) && pqErr.Code == "23505":
+		writeError(w, http.StatusConflict, "%s already exists", what)
+	default:
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// decode reads a JSON request body into v, reporting bad requests itself.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %v", err)
+		return false
+	}
+	return true
+}
+
+// pathID parses the UUID in the path segment called name.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid %s '%s'", name, r.PathValue(name))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// pathUser looks up the user named in the path.
+func (s *Server) pathUser(w http.ResponseWriter, r *http.Request) (database.User, bool) {
+	user, err := s.q.GetUser(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeDBError(w, err, "user")
+		return user, false
+	}
+	return user, true
+}
@@ -0,0 +1,342 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/api"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/dbtest"
+	"github.com/google/uuid"
+)
+
+type postPage struct {
+	Data       []api.Post `json:"data"`
+	NextCursor *uuid.UUID `json:"next_cursor"`
+}
+
+// request sends a request to srv and returns the response's status and body.
+// The token is sent as a bearer token if it isn't empty.
+func request(t *testing.T, srv *httptest.Server, method, path, token, body string) (int, []byte) {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, srv.URL+path, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, b
+}
+
+// get sends a GET request without a token and decodes the JSON response into
+// v, failing unless it's a 200.
+func get(t *testing.T, srv *httptest.Server, path string, v any) {
+	t.Helper()
+	status, body := request(t, srv, http.MethodGet, path, "", "")
+	if status != http.StatusOK {
+		t.Fatalf("GET %s: got %d %s, want 200", path, status, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+}
+
+// newServer serves the API on a test database with alice following a feed
+// with 5 posts, and bob following another one.
+func newServer(t *testing.T, token string) (*httptest.Server, *database.Queries, []database.Post) {
+	t.Helper()
+	conn := dbtest.Open(t)
+	q := database.New(conn)
+	alice := dbtest.User(t, q, "alice")
+	bob := dbtest.User(t, q, "bob")
+	feed := dbtest.Feed(t, q, alice, "Go Blog", "https://go.dev/blog/feed.atom")
+	other := dbtest.Feed(t, q, bob, "Other", "https://example.com/feed.xml")
+	posts := dbtest.Posts(t, q, feed, 5, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC))
+	dbtest.Posts(t, q, other, 2, time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC))
+
+	srv := httptest.NewServer(api.New(conn, q, token))
+	t.Cleanup(srv.Close)
+	return srv, q, posts
+}
+
+func TestAuth(t *testing.T) {
+	srv, _, _ := newServer(t, "secret")
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"wrong token", "wrong", http.StatusUnauthorized},
+		{"token", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := request(t, srv, http.MethodGet, "/v1/users", tt.token, "")
+			if status != tt.want {
+				t.Fatalf("got %d %s, want %d", status, body, tt.want)
+			}
+			if status == http.StatusUnauthorized && !strings.Contains(string(body), `"error"`) {
+				t.Errorf("got body %s, want an error", body)
+			}
+		})
+	}
+}
+
+func TestNoToken(t *testing.T) {
+	srv, _, _ := newServer(t, "")
+
+	users := struct {
+		Data []api.User `json:"data"`
+	}{}
+	get(t, srv, "/v1/users", &users)
+	if len(users.Data) != 2 {
+		t.Fatalf("got %d users, want 2", len(users.Data))
+	}
+}
+
+func TestListPosts(t *testing.T) {
+	srv, _, posts := newServer(t, "")
+
+	page := postPage{}
+	get(t, srv, "/v1/users/alice/posts", &page)
+	if len(page.Data) != len(posts) {
+		t.Fatalf("got %d posts, want %d", len(page.Data), len(posts))
+	}
+	for i, p := range page.Data {
+		if p.ID != posts[i].ID {
+			t.Errorf("post %d: got %s, want %s, newest first", i, p.Title, posts[i].Title)
+		}
+	}
+	if page.NextCursor != nil {
+		t.Errorf("got next_cursor %s on the only page, want null", page.NextCursor)
+	}
+}
+
+func TestListPostsPaging(t *testing.T) {
+	srv, _, posts := newServer(t, "")
+
+	var got []uuid.UUID
+	path := "/v1/users/alice/posts?limit=2"
+	for pages := 1; ; pages++ {
+		page := postPage{}
+		get(t, srv, path, &page)
+		if len(page.Data) > 2 {
+			t.Fatalf("page %d: got %d posts, want at most 2", pages, len(page.Data))
+		}
+		for _, p := range page.Data {
+			got = append(got, p.ID)
+		}
+		if page.NextCursor == nil {
+			if pages != 3 {
+				t.Fatalf("got %d pages, want 3", pages)
+			}
+			break
+		}
+		if last := page.Data[len(page.Data)-1].ID; *page.NextCursor != last {
+			t.Fatalf("page %d: got next_cursor %s, want the last post %s", pages, page.NextCursor, last)
+		}
+		if pages == 3 {
+			t.Fatal("got a next_cursor on the last page")
+		}
+		path = "/v1/users/alice/posts?limit=2&before=" + page.NextCursor.String()
+	}
+
+	if len(got) != len(posts) {
+		t.Fatalf("got %d posts over all pages, want %d", len(got), len(posts))
+	}
+	for i, id := range got {
+		if id != posts[i].ID {
+			t.Errorf("post %d: got %s, want %s", i, id, posts[i].ID)
+		}
+	}
+}
+
+func TestPostState(t *testing.T) {
+	srv, _, posts := newServer(t, "")
+	post := posts[1]
+	postPath := "/v1/users/alice/posts/" + post.ID.String()
+
+	tests := []struct {
+		state  string
+		filter string
+		// listedWhenSet is whether the filter lists the post when it's in
+		// the state: unread posts aren't read, starred posts are starred.
+		listedWhenSet bool
+		get           func(api.Post) bool
+	}{
+		{"read", "unread=true", false, func(p api.Post) bool { return p.Read }},
+		{"star", "starred=true", true, func(p api.Post) bool { return p.Starred }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			filtered := func() bool {
+				page := postPage{}
+				get(t, srv, "/v1/users/alice/posts?"+tt.filter, &page)
+				for _, p := range page.Data {
+					if p.ID == post.ID {
+						return true
+					}
+				}
+				return false
+			}
+			check := func(want bool) {
+				t.Helper()
+				got := api.Post{}
+				get(t, srv, postPath, &got)
+				if tt.get(got) != want {
+					t.Errorf("got %s %t, want %t", tt.state, tt.get(got), want)
+				}
+				wantListed := want
+				if !tt.listedWhenSet {
+					wantListed = !want
+				}
+				if listed := filtered(); listed != wantListed {
+					t.Errorf("got listed with %s %t, want %t", tt.filter, listed, wantListed)
+				}
+			}
+
+			check(false)
+			for _, step := range []struct {
+				method string
+				want   bool
+			}{
+				{http.MethodPut, true},
+				{http.MethodPut, true},
+				{http.MethodDelete, false},
+				{http.MethodDelete, false},
+			} {
+				status, body := request(t, srv, step.method, postPath+"/"+tt.state, "", "")
+				if status != http.StatusNoContent {
+					t.Fatalf("%s: got %d %s, want 204", step.method, status, body)
+				}
+				check(step.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv, _, _ := newServer(t, "")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"unknown route", http.MethodGet, "/v1/nothing", "", http.StatusNotFound},
+		{"unknown user", http.MethodGet, "/v1/users/carol", "", http.StatusNotFound},
+		{"posts of unknown user", http.MethodGet, "/v1/users/carol/posts", "", http.StatusNotFound},
+		{"unknown post", http.MethodGet, "/v1/users/alice/posts/" + uuid.NewString(), "", http.StatusNotFound},
+		{"star unknown post", http.MethodPut, "/v1/users/alice/posts/" + uuid.NewString() + "/star", "", http.StatusNotFound},
+		{"unknown feed", http.MethodGet, "/v1/feeds/" + uuid.NewString(), "", http.StatusNotFound},
+		{"follow unknown feed", http.MethodPost, "/v1/users/alice/follows", `{"feed_url": "https://example.org/none.xml"}`, http.StatusNotFound},
+		{"unfollow feed not followed", http.MethodDelete, "/v1/users/alice/follows/" + uuid.NewString(), "", http.StatusNotFound},
+		{"existing user", http.MethodPost, "/v1/users", `{"name": "alice"}`, http.StatusConflict},
+		{"feed already followed", http.MethodPost, "/v1/users/bob/follows", `{"feed_url": "https://example.com/feed.xml"}`, http.StatusConflict},
+		{"existing feed", http.MethodPost, "/v1/feeds", `{"name": "Again", "url": "https://go.dev/blog/feed.atom", "user": "bob"}`, http.StatusConflict},
+		{"invalid JSON", http.MethodPost, "/v1/users", `{"name":`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/v1/users", `{"name": "carol", "admin": true}`, http.StatusBadRequest},
+		{"empty name", http.MethodPost, "/v1/users", `{"name": " "}`, http.StatusBadRequest},
+		{"feed without url", http.MethodPost, "/v1/feeds", `{"name": "No URL", "user": "alice"}`, http.StatusBadRequest},
+		{"invalid post ID", http.MethodGet, "/v1/users/alice/posts/1", "", http.StatusBadRequest},
+		{"invalid feed ID", http.MethodDelete, "/v1/users/alice/follows/1", "", http.StatusBadRequest},
+		{"limit too small", http.MethodGet, "/v1/users/alice/posts?limit=0", "", http.StatusBadRequest},
+		{"limit too big", http.MethodGet, "/v1/users/alice/posts?limit=101", "", http.StatusBadRequest},
+		{"invalid sort", http.MethodGet, "/v1/users/alice/posts?sort=title", "", http.StatusBadRequest},
+		{"invalid date", http.MethodGet, "/v1/users/alice/posts?from=someday", "", http.StatusBadRequest},
+		{"invalid filter", http.MethodGet, "/v1/users/alice/posts?unread=maybe", "", http.StatusBadRequest},
+		{"invalid cursor", http.MethodGet, "/v1/users/alice/posts?before=1", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := request(t, srv, tt.method, tt.path, "", tt.body)
+			if status != tt.want {
+				t.Fatalf("got %d %s, want %d", status, body, tt.want)
+			}
+			res := struct {
+				Error string `json:"error"`
+			}{}
+			if err := json.Unmarshal(body, &res); err != nil || res.Error == "" {
+				t.Errorf("got body %s, want a JSON error", body)
+			}
+		})
+	}
+}
+
+func TestCreateFeed(t *testing.T) {
+	srv, _, _ := newServer(t, "")
+
+	status, body := request(t, srv, http.MethodPost, "/v1/feeds", "", `{"name": "New", "url": "https://example.org/new.xml", "user": "alice"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("got %d %s, want 201", status, body)
+	}
+	feed := api.Feed{}
+	if err := json.Unmarshal(body, &feed); err != nil {
+		t.Fatal(err)
+	}
+
+	follows := struct {
+		Data []api.Follow `json:"data"`
+	}{}
+	get(t, srv, "/v1/users/alice/follows", &follows)
+	followed := false
+	for _, f := range follows.Data {
+		followed = followed || f.FeedID == feed.ID
+	}
+	if !followed {
+		t.Errorf("got follows %+v, want %s followed", follows.Data, feed.URL)
+	}
+}
+
+func TestUnfollow(t *testing.T) {
+	srv, q, _ := newServer(t, "")
+	alice, err := q.GetUser(t.Context(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := q.GetFeed(t.Context(), "https://go.dev/blog/feed.atom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := q.GetFeed(t.Context(), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbtest.Follow(t, q, alice, other)
+
+	status, body := request(t, srv, http.MethodDelete, "/v1/users/alice/follows/"+feed.ID.String(), "", "")
+	if status != http.StatusNoContent {
+		t.Fatalf("got %d %s, want 204", status, body)
+	}
+
+	follows := struct {
+		Data []api.Follow `json:"data"`
+	}{}
+	get(t, srv, "/v1/users/alice/follows", &follows)
+	if len(follows.Data) != 1 || follows.Data[0].FeedID != other.ID {
+		t.Errorf("got follows %+v, want only %s", follows.Data, other.Url)
+	}
+}
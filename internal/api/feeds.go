@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+type Feed struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	URL           string     `json:"url"`
+	SiteURL       string     `json:"site_url"`
+	FullText      bool       `json:"full_text"`
+	UserID        uuid.UUID  `json:"user_id"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func toFeed(f database.Feed) Feed {
+	feed := Feed{f.ID, f.Name, f.Url, f.SiteUrl, f.FullText, f.UserID, nil, f.CreatedAt, f.UpdatedAt}
+	if f.LastFetchedAt.Valid {
+		feed.LastFetchedAt = &f.LastFetchedAt.Time
+	}
+	return feed
+}
+
+func (s *Server) listFeeds(w http.ResponseWriter, r *http.Request) {
+	feeds, err := s.q.GetFeeds(r.Context())
+	if err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	res := list[Feed]{Data: []Feed{}}
+	for _, f := range feeds {
+		res.Data = append(res.Data, toFeed(f))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// createFeed adds a feed, which the user adding it follows, like the addfeed
+// command. Both happen in a transaction, so a feed isn't left behind without
+// its follow if following it fails.
+func (s *Server) createFeed(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+		User string `json:"user"`
+	}{}
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.URL) == "" {
+		writeError(w, http.StatusBadRequest, "name and url are required")
+		return
+	}
+	user, err := s.q.GetUser(r.Context(), req.User)
+	if err != nil {
+		writeDBError(w, err, "user")
+		return
+	}
+	tx, err := s.conn.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	defer tx.Rollback()
+	qtx := s.q.WithTx(tx)
+
+	feed, err := qtx.AddFeed(r.Context(), database.AddFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      req.Name,
+		Url:       req.URL,
+		UserID:    user.ID,
+	})
+	if err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	_, err = qtx.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		writeDBError(w, err, "follow")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	writeJSON(w, http.StatusCreated, toFeed(feed))
+}
+
+func (s *Server) getFeed(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	feed, err := s.q.GetFeedByID(r.Context(), id)
+	if err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	writeJSON(w, http.StatusOK, toFeed(feed))
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/dates"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type Post struct {
+	ID          uuid.UUID `json:"id"`
+	FeedID      uuid.UUID `json:"feed_id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Content     string    `json:"content,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	Read        bool      `json:"read"`
+	Starred     bool      `json:"starred"`
+}
+
+type postPage struct {
+	Data []Post `json:"data"`
+	// NextCursor is passed as before to get the next page, and is null on the
+	// last page.
+	NextCursor *uuid.UUID `json:"next_cursor"`
+}
+
+// listPosts lists the posts from the feeds the user follows, newest first. It
+// takes the same filters as the browse command as query parameters, and pages
+// through the posts with a cursor.
+func (s *Server) listPosts(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			writeError(w, http.StatusBadRequest, "limit must be a number from 1 to %d", maxPageSize)
+			return
+		}
+		limit = n
+	}
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = "published"
+	}
+	if sort != "published" && sort != "fetched" {
+		writeError(w, http.StatusBadRequest, "sort must be either 'published' or 'fetched'")
+		return
+	}
+	var from, to sql.NullTime
+	for _, p := range []struct {
+		name  string
+		t     *sql.NullTime
+		parse func(string) (time.Time, error)
+	}{{"from", &from, dates.Start}, {"to", &to, dates.End}} {
+		v := query.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := p.parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid date '%s'", v)
+			return
+		}
+		*p.t = sql.NullTime{Time: t, Valid: true}
+	}
+	unread, ok := boolParam(w, query.Get("unread"), "unread")
+	if !ok {
+		return
+	}
+	starred, ok := boolParam(w, query.Get("starred"), "starred")
+	if !ok {
+		return
+	}
+	before := uuid.NullUUID{}
+	if v := query.Get("before"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor '%s'", v)
+			return
+		}
+		before = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	// One more post than asked for is fetched to tell if there's a next page.
+	rows, err := s.q.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+		UserID:      user.ID,
+		Feed:        optionalString(query.Get("feed")),
+		Folder:      optionalString(query.Get("folder")),
+		Tag:         optionalString(strings.ToLower(query.Get("tag"))),
+		From:        from,
+		Sort:        sort,
+		To:          to,
+		Search:      optionalString(query.Get("search")),
+		UnreadOnly:  unread,
+		StarredOnly: starred,
+		Before:      before,
+		Limit:       int32(limit + 1),
+	})
+	if err != nil {
+		writeDBError(w, err, "post")
+		return
+	}
+
+	res := postPage{Data: []Post{}}
+	for i, row := range rows {
+		if i == limit {
+			res.NextCursor = &rows[i-1].ID
+			break
+		}
+		res.Data = append(res.Data, Post{
+			ID:          row.ID,
+			FeedID:      row.FeedID,
+			Title:       row.Title,
+			URL:         row.Url,
+			Description: row.Description,
+			PublishedAt: row.PublishedAt,
+			CreatedAt:   row.CreatedAt,
+			Read:        row.Read,
+			Starred:     row.Starred,
+		})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getPost(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	post, err := s.q.GetPost(r.Context(), id)
+	if err != nil {
+		writeDBError(w, err, "post")
+		return
+	}
+	state, err := s.q.GetPostState(r.Context(), database.GetPostStateParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		writeDBError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, Post{
+		ID:          post.ID,
+		FeedID:      post.FeedID,
+		Title:       post.Title,
+		URL:         post.Url,
+		Description: post.Description,
+		Content:     post.Content,
+		PublishedAt: post.PublishedAt,
+		CreatedAt:   post.CreatedAt,
+		Read:        state.Read,
+		Starred:     state.Starred,
+	})
+}
+
+// setPostState marks a post read or starred with PUT, and unread or unstarred
+// with DELETE.
+func (s *Server) setPostState(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	post, err := s.q.GetPost(r.Context(), id)
+	if err != nil {
+		writeDBError(w, err, "post")
+		return
+	}
+
+	set := r.Method == http.MethodPut
+	if strings.HasSuffix(r.URL.Path, "/read") {
+		if set {
+			err = s.q.MarkPostRead(r.Context(), database.MarkPostReadParams{
+				CreatedAt: time.Now(),
+				UserID:    user.ID,
+				PostID:    post.ID,
+			})
+		} else {
+			err = s.q.MarkPostUnread(r.Context(), database.MarkPostUnreadParams{
+				UserID: user.ID,
+				PostID: post.ID,
+			})
+		}
+	} else {
+		if set {
+			err = s.q.StarPost(r.Context(), database.StarPostParams{
+				CreatedAt: time.Now(),
+				UserID:    user.ID,
+				PostID:    post.ID,
+			})
+		} else {
+			err = s.q.UnstarPost(r.Context(), database.UnstarPostParams{
+				UserID: user.ID,
+				PostID: post.ID,
+			})
+		}
+	}
+	if err != nil {
+		writeDBError(w, err, "post")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func boolParam(w http.ResponseWriter, v, name string) (bool, bool) {
+	if v == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s must be true or false", name)
+		return false, false
+	}
+	return b, true
+}
+
+func optionalString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
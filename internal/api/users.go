@@ -0,0 +1,149 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toUser(u database.User) User {
+	return User{u.ID, u.Name, u.CreatedAt, u.UpdatedAt}
+}
+
+type Follow struct {
+	FeedID    uuid.UUID `json:"feed_id"`
+	FeedName  string    `json:"feed_name"`
+	FeedURL   string    `json:"feed_url"`
+	Folder    *string   `json:"folder"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.q.GetUsers(r.Context())
+	if err != nil {
+		writeDBError(w, err, "user")
+		return
+	}
+	res := list[User]{Data: []User{}}
+	for _, u := range users {
+		res.Data = append(res.Data, toUser(u))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		Name string `json:"name"`
+	}{}
+	if !decode(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	user, err := s.q.CreateUser(r.Context(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      req.Name,
+	})
+	if err != nil {
+		writeDBError(w, err, "user")
+		return
+	}
+	writeJSON(w, http.StatusCreated, toUser(user))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toUser(user))
+}
+
+func (s *Server) listFollows(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	feedFollows, err := s.q.GetFeedFollowsForUser(r.Context(), user.Name)
+	if err != nil {
+		writeDBError(w, err, "follow")
+		return
+	}
+	res := list[Follow]{Data: []Follow{}}
+	for _, ff := range feedFollows {
+		follow := Follow{ff.FeedID, ff.FeedName, ff.FeedUrl, nil, ff.CreatedAt}
+		if ff.FolderName.Valid {
+			follow.Folder = &ff.FolderName.String
+		}
+		res.Data = append(res.Data, follow)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createFollow(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	req := struct {
+		FeedURL string `json:"feed_url"`
+	}{}
+	if !decode(w, r, &req) {
+		return
+	}
+	feed, err := s.q.GetFeed(r.Context(), req.FeedURL)
+	if err != nil {
+		writeDBError(w, err, "feed")
+		return
+	}
+	ff, err := s.q.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		writeDBError(w, err, "follow")
+		return
+	}
+	writeJSON(w, http.StatusCreated, Follow{ff.FeedID, ff.FeedName, feed.Url, nil, ff.CreatedAt})
+}
+
+func (s *Server) deleteFollow(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.pathUser(w, r)
+	if !ok {
+		return
+	}
+	feedID, ok := pathID(w, r, "feed_id")
+	if !ok {
+		return
+	}
+	deleted, err := s.q.DeleteFeedFollowByFeed(r.Context(), database.DeleteFeedFollowByFeedParams{
+		UserID: user.ID,
+		FeedID: feedID,
+	})
+	if err != nil {
+		writeDBError(w, err, "follow")
+		return
+	}
+	if deleted == 0 {
+		writeError(w, http.StatusNotFound, "follow not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,252 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/R0Xps/gatorcli/internal/sanitize"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type Format string
+
+const (
+	HTML     Format = "html"
+	Markdown Format = "markdown"
+)
+
+var Formats = []Format{HTML, Markdown}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown archive format '%s', expected one of html, markdown", s)
+}
+
+// Extension is the file name extension of the format.
+func (f Format) Extension() string {
+	if f == Markdown {
+		return ".md"
+	}
+	return ".html"
+}
+
+// maxImageSize limits the size of the images downloaded for an article.
+const maxImageSize = 10 << 20
+
+// Article is a post to archive.
+type Article struct {
+	Title     string
+	URL       string
+	Feed      string
+	Published time.Time
+	// Content is the HTML of the article.
+	Content string
+}
+
+// FileName returns a file name for the article: its publication date and a
+// slug of its title, followed by suffix to tell apart articles with the same
+// title.
+func FileName(a Article, suffix string, format Format) string {
+	slug := strings.FieldsFunc(strings.ToLower(a.Title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	name := strings.Join(slug, "-")
+	if runes := []rune(name); len(runes) > 60 {
+		name = strings.TrimRight(string(runes[:60]), "-")
+	}
+	if name == "" {
+		name = "post"
+	}
+	return fmt.Sprintf("%s-%s-%s%s", a.Published.Format("2006-01-02"), name, suffix, format.Extension())
+}
+
+// Save writes the article to path in the given format, creating the
+// directories it's in. Scripts, styles and embedded frames are removed, and
+// links are made absolute. With images, the article's images are downloaded
+// and embedded in HTML files as data URLs, or stored next to Markdown files in
+// a directory named after them. Images that can't be downloaded are left
+// linked to their original address.
+func Save(ctx context.Context, a Article, format Format, path string, images bool) error {
+	body, err := sanitize.Body(a.Content, a.URL)
+	if err != nil {
+		return err
+	}
+
+	if images {
+		filesDir := strings.TrimSuffix(path, filepath.Ext(path)) + "_files"
+		if err := saveImages(ctx, body, format, filesDir); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case HTML:
+		var content bytes.Buffer
+		for c := body.FirstChild; c != nil; c = c.NextSibling {
+			if err := html.Render(&content, c); err != nil {
+				return err
+			}
+		}
+		err = htmlTemplate.Execute(&buf, struct {
+			Article
+			Body template.HTML
+		}{a, template.HTML(content.String())})
+		if err != nil {
+			return err
+		}
+	case Markdown:
+		fmt.Fprintf(&buf, "# %s\n\n", markdownEscaper.Replace(a.Title))
+		if a.Feed != "" {
+			fmt.Fprintf(&buf, "%s · ", markdownEscaper.Replace(a.Feed))
+		}
+		fmt.Fprintf(&buf, "%s · <%s>\n\n", a.Published.Format("January 2, 2006"), a.URL)
+		buf.WriteString(toMarkdown(body))
+		buf.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+var htmlTemplate = template.Must(template.New("article").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 40em; margin: 2em auto; padding: 0 1em; font: 18px/1.6 Georgia, serif; color: #222; }
+img { max-width: 100%; height: auto; }
+pre { overflow-x: auto; }
+.meta { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<article>
+<h1>{{.Title}}</h1>
+<p class="meta">{{if .Feed}}{{.Feed}} · {{end}}{{.Published.Format "January 2, 2006"}} · <a href="{{.URL}}">Original</a></p>
+{{.Body}}
+</article>
+</body>
+</html>
+`))
+
+func saveImages(ctx context.Context, body *html.Node, format Format, filesDir string) error {
+	saved := map[string]string{}
+	var err error
+	walk(body, func(n *html.Node) {
+		if err != nil || n.Type != html.ElementNode || n.DataAtom != atom.Img {
+			return
+		}
+		for i, a := range n.Attr {
+			if a.Key != "src" || !strings.HasPrefix(a.Val, "http") {
+				continue
+			}
+			if local, ok := saved[a.Val]; ok {
+				n.Attr[i].Val = local
+				continue
+			}
+			data, contentType, fetchErr := fetchImage(ctx, a.Val)
+			if fetchErr != nil {
+				continue
+			}
+			local := ""
+			if format == HTML {
+				local = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+			} else {
+				name := fmt.Sprintf("%02d%s", len(saved)+1, imageExtension(contentType, a.Val))
+				if err = os.MkdirAll(filesDir, 0755); err != nil {
+					return
+				}
+				if err = os.WriteFile(filepath.Join(filesDir, name), data, 0644); err != nil {
+					return
+				}
+				local = filepath.Base(filesDir) + "/" + name
+			}
+			saved[a.Val] = local
+			n.Attr[i].Val = local
+		}
+	})
+	return err
+}
+
+func fetchImage(ctx context.Context, imageURL string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	client := http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s", res.Status)
+	}
+	contentType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("not an image: %s", contentType)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxImageSize {
+		return nil, "", fmt.Errorf("image too large")
+	}
+	return data, contentType, nil
+}
+
+func imageExtension(contentType, imageURL string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	case "image/avif":
+		return ".avif"
+	}
+	if u, err := url.Parse(imageURL); err == nil && filepath.Ext(u.Path) != "" {
+		return filepath.Ext(u.Path)
+	}
+	return ".img"
+}
+
+// walk calls f for n and all of its descendants, in document order.
+func walk(n *html.Node, f func(*html.Node)) {
+	f(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
@@ -0,0 +1,202 @@
+package archive
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var markdownBlocks = map[atom.Atom]bool{
+	atom.Article:    true,
+	atom.Aside:      true,
+	atom.Blockquote: true,
+	atom.Dd:         true,
+	atom.Details:    true,
+	atom.Div:        true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Figcaption: true,
+	atom.Figure:     true,
+	atom.Footer:     true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Header:     true,
+	atom.Hr:         true,
+	atom.Li:         true,
+	atom.Main:       true,
+	atom.Nav:        true,
+	atom.Ol:         true,
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Section:    true,
+	atom.Summary:    true,
+	atom.Table:      true,
+	atom.Tbody:      true,
+	atom.Thead:      true,
+	atom.Tfoot:      true,
+	atom.Tr:         true,
+	atom.Ul:         true,
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// toMarkdown converts the children of n to Markdown.
+func toMarkdown(n *html.Node) string {
+	blocks := []string{}
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || !markdownBlocks[c.DataAtom] {
+			inline.WriteString(markdownInline(c))
+			continue
+		}
+		flush()
+		if block := markdownBlock(c); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	flush()
+	return strings.Join(blocks, "\n\n")
+}
+
+func markdownBlock(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := strings.TrimSpace(markdownInline(n))
+		if text == "" {
+			return ""
+		}
+		level := int(n.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + text
+	case atom.Hr:
+		return "---"
+	case atom.Pre:
+		return "```\n" + strings.TrimRight(textContent(n), "\n") + "\n```"
+	case atom.Blockquote:
+		return prefixLines(toMarkdown(n), "> ", ">")
+	case atom.Ul, atom.Ol:
+		items := []string{}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				continue
+			}
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = fmt.Sprintf("%d. ", len(items)+1)
+			}
+			item := prefixLines(toMarkdown(c), strings.Repeat(" ", len(marker)), "")
+			items = append(items, marker+strings.TrimLeft(item, " "))
+		}
+		return strings.Join(items, "\n")
+	}
+	return toMarkdown(n)
+}
+
+func markdownInline(n *html.Node) string {
+	if n.Type == html.TextNode {
+		text := strings.Join(strings.Fields(n.Data), " ")
+		if text == "" {
+			if n.Data != "" {
+				return " "
+			}
+			return ""
+		}
+		if strings.TrimLeft(n.Data, " \t\r\n") != n.Data {
+			text = " " + text
+		}
+		if strings.TrimRight(n.Data, " \t\r\n") != n.Data {
+			text += " "
+		}
+		return markdownEscaper.Replace(text)
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(markdownInline(c))
+	}
+	inner := sb.String()
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "  \n"
+	case atom.Img:
+		return fmt.Sprintf("![%s](%s)", markdownEscaper.Replace(attr(n, "alt")), attr(n, "src"))
+	case atom.A:
+		href := attr(n, "href")
+		if href == "" || strings.TrimSpace(inner) == "" {
+			return inner
+		}
+		return fmt.Sprintf("[%s](%s)", strings.TrimSpace(inner), href)
+	case atom.Strong, atom.B:
+		if strings.TrimSpace(inner) == "" {
+			return inner
+		}
+		return "**" + strings.TrimSpace(inner) + "**"
+	case atom.Em, atom.I:
+		if strings.TrimSpace(inner) == "" {
+			return inner
+		}
+		return "*" + strings.TrimSpace(inner) + "*"
+	case atom.Code:
+		return "`" + textContent(n) + "`"
+	case atom.Td, atom.Th:
+		return strings.TrimSpace(inner) + " "
+	}
+	return inner
+}
+
+// prefixLines adds prefix to the start of every line of s, or empty to blank
+// lines.
+func prefixLines(s, prefix, empty string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = empty
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
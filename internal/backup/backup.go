@@ -0,0 +1,792 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+// Version is the version of the archive format written by Write. Restore
+// accepts archives up to this version.
+const Version = 10
+
+const postsPageSize = 500
+
+// An archive is a JSON Lines file: a header line, followed by one line per
+// record. Records only reference records that appear before them.
+type header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+}
+
+type Feed struct {
+	ID            uuid.UUID  `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Name          string     `json:"name"`
+	Url           string     `json:"url"`
+	SiteUrl       string     `json:"site_url"`
+	FullText      bool       `json:"full_text"`
+	UserID        uuid.UUID  `json:"user_id"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+}
+
+type Folder struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FeedID    uuid.UUID  `json:"feed_id"`
+	FolderID  *uuid.UUID `json:"folder_id"`
+}
+
+type Post struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Title       string    `json:"title"`
+	Url         string    `json:"url"`
+	Description string    `json:"description"`
+	PublishedAt time.Time `json:"published_at"`
+	FeedID      uuid.UUID `json:"feed_id"`
+	Content     string    `json:"content"`
+}
+
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+type PostTag struct {
+	CreatedAt time.Time `json:"created_at"`
+	PostID    uuid.UUID `json:"post_id"`
+	TagID     uuid.UUID `json:"tag_id"`
+}
+
+// PostState is the data of "post_read", "post_star" and "post_hide" records.
+type PostState struct {
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	PostID    uuid.UUID `json:"post_id"`
+}
+
+// PostArchive is a post saved for offline reading. Only its path is in the
+// archive, the saved files have to be copied along with it.
+type PostArchive struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	PostID    uuid.UUID `json:"post_id"`
+	Format    string    `json:"format"`
+	Path      string    `json:"path"`
+}
+
+// FeverKey is a user's key for the Fever and Google Reader APIs. It's derived
+// from the user's name, which restoring keeps, so it stays valid.
+type FeverKey struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ApiKey    string    `json:"api_key"`
+}
+
+// FeedToken is the token that lets feed readers fetch a user's published
+// feeds.
+type FeedToken struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Token     string    `json:"token"`
+}
+
+// Webhook is a webhook with its secret. Its deliveries aren't backed up.
+type Webhook struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FeedID    *uuid.UUID `json:"feed_id"`
+	Url       string     `json:"url"`
+	Secret    string     `json:"secret"`
+}
+
+type Rule struct {
+	ID           uuid.UUID  `json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	UserID       uuid.UUID  `json:"user_id"`
+	FeedID       *uuid.UUID `json:"feed_id"`
+	TitleRegex   string     `json:"title_regex"`
+	ContentRegex string     `json:"content_regex"`
+	Action       string     `json:"action"`
+}
+
+type WatchWord struct {
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	UserID         uuid.UUID `json:"user_id"`
+	Word           string    `json:"word"`
+	Regex          bool      `json:"regex"`
+	NotifyWebhooks bool      `json:"notify_webhooks"`
+	NotifyEmail    bool      `json:"notify_email"`
+}
+
+type Alert struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	WatchWordID uuid.UUID `json:"watch_word_id"`
+	PostID      uuid.UUID `json:"post_id"`
+	Snippet     string    `json:"snippet"`
+}
+
+// Stats counts the records of each type written to or read from an archive.
+type Stats map[string]int
+
+// Write dumps the whole database to w as an archive.
+func Write(ctx context.Context, q *database.Queries, w io.Writer) (Stats, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	stats := Stats{}
+
+	write := func(recordType string, data any) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		stats[recordType]++
+		return encoder.Encode(record{Type: recordType, Data: raw})
+	}
+
+	err := encoder.Encode(header{Format: "gator-backup", Version: Version, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := q.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if err := write("user", User{u.ID, u.CreatedAt, u.UpdatedAt, u.Name, u.Email}); err != nil {
+			return nil, err
+		}
+	}
+
+	feeds, err := q.GetFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range feeds {
+		feed := Feed{f.ID, f.CreatedAt, f.UpdatedAt, f.Name, f.Url, f.SiteUrl, f.FullText, f.UserID, nil}
+		if f.LastFetchedAt.Valid {
+			feed.LastFetchedAt = &f.LastFetchedAt.Time
+		}
+		if err := write("feed", feed); err != nil {
+			return nil, err
+		}
+	}
+
+	folders, err := q.GetAllFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range folders {
+		if err := write("folder", Folder{f.ID, f.CreatedAt, f.UpdatedAt, f.Name, f.UserID}); err != nil {
+			return nil, err
+		}
+	}
+
+	feedFollows, err := q.GetAllFeedFollows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ff := range feedFollows {
+		feedFollow := FeedFollow{ff.ID, ff.CreatedAt, ff.UpdatedAt, ff.UserID, ff.FeedID, nil}
+		if ff.FolderID.Valid {
+			feedFollow.FolderID = &ff.FolderID.UUID
+		}
+		if err := write("feed_follow", feedFollow); err != nil {
+			return nil, err
+		}
+	}
+
+	lastID := uuid.Nil
+	for {
+		posts, err := q.GetPostsPage(ctx, database.GetPostsPageParams{
+			ID:    lastID,
+			Limit: postsPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range posts {
+			if err := write("post", Post{p.ID, p.CreatedAt, p.UpdatedAt, p.Title, p.Url, p.Description, p.PublishedAt, p.FeedID, p.Content}); err != nil {
+				return nil, err
+			}
+		}
+		if len(posts) < postsPageSize {
+			break
+		}
+		lastID = posts[len(posts)-1].ID
+	}
+
+	tags, err := q.GetAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		if err := write("tag", Tag{t.ID, t.CreatedAt, t.UpdatedAt, t.Name, t.UserID}); err != nil {
+			return nil, err
+		}
+	}
+
+	postTags, err := q.GetAllPostTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pt := range postTags {
+		if err := write("post_tag", PostTag{pt.CreatedAt, pt.PostID, pt.TagID}); err != nil {
+			return nil, err
+		}
+	}
+
+	postReads, err := q.GetAllPostReads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range postReads {
+		if err := write("post_read", PostState{pr.CreatedAt, pr.UserID, pr.PostID}); err != nil {
+			return nil, err
+		}
+	}
+
+	postStars, err := q.GetAllPostStars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ps := range postStars {
+		if err := write("post_star", PostState{ps.CreatedAt, ps.UserID, ps.PostID}); err != nil {
+			return nil, err
+		}
+	}
+
+	postArchives, err := q.GetAllPostArchives(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pa := range postArchives {
+		if err := write("post_archive", PostArchive{pa.ID, pa.CreatedAt, pa.UpdatedAt, pa.UserID, pa.PostID, pa.Format, pa.Path}); err != nil {
+			return nil, err
+		}
+	}
+
+	feverKeys, err := q.GetAllFeverKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range feverKeys {
+		if err := write("fever_key", FeverKey{fk.UserID, fk.CreatedAt, fk.UpdatedAt, fk.ApiKey}); err != nil {
+			return nil, err
+		}
+	}
+
+	feedTokens, err := q.GetAllFeedTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ft := range feedTokens {
+		if err := write("feed_token", FeedToken{ft.UserID, ft.CreatedAt, ft.UpdatedAt, ft.Token}); err != nil {
+			return nil, err
+		}
+	}
+
+	webhooks, err := q.GetAllWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, wh := range webhooks {
+		webhook := Webhook{wh.ID, wh.CreatedAt, wh.UpdatedAt, wh.UserID, nil, wh.Url, wh.Secret}
+		if wh.FeedID.Valid {
+			webhook.FeedID = &wh.FeedID.UUID
+		}
+		if err := write("webhook", webhook); err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := q.GetAllRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ru := range rules {
+		rule := Rule{ru.ID, ru.CreatedAt, ru.UpdatedAt, ru.UserID, nil, ru.TitleRegex, ru.ContentRegex, ru.Action}
+		if ru.FeedID.Valid {
+			rule.FeedID = &ru.FeedID.UUID
+		}
+		if err := write("rule", rule); err != nil {
+			return nil, err
+		}
+	}
+
+	postHides, err := q.GetAllPostHides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ph := range postHides {
+		if err := write("post_hide", PostState{ph.CreatedAt, ph.UserID, ph.PostID}); err != nil {
+			return nil, err
+		}
+	}
+
+	watchWords, err := q.GetAllWatchWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ww := range watchWords {
+		if err := write("watch_word", WatchWord{ww.ID, ww.CreatedAt, ww.UpdatedAt, ww.UserID, ww.Word, ww.Regex, ww.NotifyWebhooks, ww.NotifyEmail}); err != nil {
+			return nil, err
+		}
+	}
+
+	alerts, err := q.GetAllAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range alerts {
+		if err := write("alert", Alert{a.ID, a.CreatedAt, a.WatchWordID, a.PostID, a.Snippet}); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, bw.Flush()
+}
+
+// Restore merges an archive into the database. Records that already exist,
+// matched by their natural keys (user names, feed and post URLs, folder and
+// tag names per user), are kept as they are, so restoring the same archive
+// twice has no further effect. Records are given new IDs when they're
+// inserted, and references between them are remapped accordingly.
+func Restore(ctx context.Context, q *database.Queries, r io.Reader) (Stats, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+
+	h := header{}
+	if err := decoder.Decode(&h); err != nil {
+		return nil, fmt.Errorf("couldn't read backup header: %w", err)
+	}
+	if h.Format != "gator-backup" {
+		return nil, fmt.Errorf("not a gator backup file")
+	}
+	if h.Version < 1 || h.Version > Version {
+		return nil, fmt.Errorf("unsupported backup version %d, expected at most %d", h.Version, Version)
+	}
+
+	rs := restorer{q: q, ids: map[uuid.UUID]uuid.UUID{}}
+	stats := Stats{}
+	for line := 2; ; line++ {
+		rec := record{}
+		err := decoder.Decode(&rec)
+		if err == io.EOF {
+			return stats, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err = rs.restore(ctx, rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		stats[rec.Type]++
+	}
+}
+
+type restorer struct {
+	q *database.Queries
+	// ids maps the IDs in the archive to the IDs of the matching rows in the
+	// database.
+	ids map[uuid.UUID]uuid.UUID
+}
+
+func (r *restorer) lookup(recordType string, id uuid.UUID) (uuid.UUID, error) {
+	newID, ok := r.ids[id]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("%s references unknown record %s", recordType, id)
+	}
+	return newID, nil
+}
+
+func (r *restorer) restore(ctx context.Context, rec record) error {
+	q := r.q
+	switch rec.Type {
+	case "user":
+		u := User{}
+		if err := json.Unmarshal(rec.Data, &u); err != nil {
+			return err
+		}
+		id, err := q.RestoreUser(ctx, database.RestoreUserParams{
+			ID:        uuid.New(),
+			CreatedAt: u.CreatedAt,
+			UpdatedAt: u.UpdatedAt,
+			Name:      u.Name,
+			Email:     u.Email,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[u.ID] = id
+	case "feed":
+		f := Feed{}
+		if err := json.Unmarshal(rec.Data, &f); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, f.UserID)
+		if err != nil {
+			return err
+		}
+		lastFetchedAt := sql.NullTime{}
+		if f.LastFetchedAt != nil {
+			lastFetchedAt = sql.NullTime{Time: *f.LastFetchedAt, Valid: true}
+		}
+		id, err := q.RestoreFeed(ctx, database.RestoreFeedParams{
+			ID:            uuid.New(),
+			CreatedAt:     f.CreatedAt,
+			UpdatedAt:     f.UpdatedAt,
+			Name:          f.Name,
+			Url:           f.Url,
+			UserID:        userID,
+			LastFetchedAt: lastFetchedAt,
+			SiteUrl:       f.SiteUrl,
+			FullText:      f.FullText,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[f.ID] = id
+	case "folder":
+		f := Folder{}
+		if err := json.Unmarshal(rec.Data, &f); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, f.UserID)
+		if err != nil {
+			return err
+		}
+		id, err := q.RestoreFolder(ctx, database.RestoreFolderParams{
+			ID:        uuid.New(),
+			CreatedAt: f.CreatedAt,
+			UpdatedAt: f.UpdatedAt,
+			Name:      f.Name,
+			UserID:    userID,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[f.ID] = id
+	case "feed_follow":
+		ff := FeedFollow{}
+		if err := json.Unmarshal(rec.Data, &ff); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, ff.UserID)
+		if err != nil {
+			return err
+		}
+		feedID, err := r.lookup(rec.Type, ff.FeedID)
+		if err != nil {
+			return err
+		}
+		folderID := uuid.NullUUID{}
+		if ff.FolderID != nil {
+			folderID.UUID, err = r.lookup(rec.Type, *ff.FolderID)
+			if err != nil {
+				return err
+			}
+			folderID.Valid = true
+		}
+		return q.RestoreFeedFollow(ctx, database.RestoreFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: ff.CreatedAt,
+			UpdatedAt: ff.UpdatedAt,
+			UserID:    userID,
+			FeedID:    feedID,
+			FolderID:  folderID,
+		})
+	case "post":
+		p := Post{}
+		if err := json.Unmarshal(rec.Data, &p); err != nil {
+			return err
+		}
+		feedID, err := r.lookup(rec.Type, p.FeedID)
+		if err != nil {
+			return err
+		}
+		id, err := q.RestorePost(ctx, database.RestorePostParams{
+			ID:          uuid.New(),
+			CreatedAt:   p.CreatedAt,
+			UpdatedAt:   p.UpdatedAt,
+			Title:       p.Title,
+			Url:         p.Url,
+			Description: p.Description,
+			PublishedAt: p.PublishedAt,
+			FeedID:      feedID,
+			Content:     p.Content,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[p.ID] = id
+	case "tag":
+		t := Tag{}
+		if err := json.Unmarshal(rec.Data, &t); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, t.UserID)
+		if err != nil {
+			return err
+		}
+		id, err := q.RestoreTag(ctx, database.RestoreTagParams{
+			ID:        uuid.New(),
+			CreatedAt: t.CreatedAt,
+			UpdatedAt: t.UpdatedAt,
+			Name:      t.Name,
+			UserID:    userID,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[t.ID] = id
+	case "post_tag":
+		pt := PostTag{}
+		if err := json.Unmarshal(rec.Data, &pt); err != nil {
+			return err
+		}
+		postID, err := r.lookup(rec.Type, pt.PostID)
+		if err != nil {
+			return err
+		}
+		tagID, err := r.lookup(rec.Type, pt.TagID)
+		if err != nil {
+			return err
+		}
+		return q.RestorePostTag(ctx, database.RestorePostTagParams{
+			CreatedAt: pt.CreatedAt,
+			PostID:    postID,
+			TagID:     tagID,
+		})
+	case "post_read", "post_star", "post_hide":
+		ps := PostState{}
+		if err := json.Unmarshal(rec.Data, &ps); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, ps.UserID)
+		if err != nil {
+			return err
+		}
+		postID, err := r.lookup(rec.Type, ps.PostID)
+		if err != nil {
+			return err
+		}
+		switch rec.Type {
+		case "post_read":
+			return q.MarkPostRead(ctx, database.MarkPostReadParams{
+				CreatedAt: ps.CreatedAt,
+				UserID:    userID,
+				PostID:    postID,
+			})
+		case "post_hide":
+			return q.HidePost(ctx, database.HidePostParams{
+				CreatedAt: ps.CreatedAt,
+				UserID:    userID,
+				PostID:    postID,
+			})
+		}
+		return q.StarPost(ctx, database.StarPostParams{
+			CreatedAt: ps.CreatedAt,
+			UserID:    userID,
+			PostID:    postID,
+		})
+	case "post_archive":
+		pa := PostArchive{}
+		if err := json.Unmarshal(rec.Data, &pa); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, pa.UserID)
+		if err != nil {
+			return err
+		}
+		postID, err := r.lookup(rec.Type, pa.PostID)
+		if err != nil {
+			return err
+		}
+		return q.RestorePostArchive(ctx, database.RestorePostArchiveParams{
+			ID:        uuid.New(),
+			CreatedAt: pa.CreatedAt,
+			UpdatedAt: pa.UpdatedAt,
+			UserID:    userID,
+			PostID:    postID,
+			Format:    pa.Format,
+			Path:      pa.Path,
+		})
+	case "fever_key":
+		fk := FeverKey{}
+		if err := json.Unmarshal(rec.Data, &fk); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, fk.UserID)
+		if err != nil {
+			return err
+		}
+		return q.RestoreFeverKey(ctx, database.RestoreFeverKeyParams{
+			UserID:    userID,
+			CreatedAt: fk.CreatedAt,
+			UpdatedAt: fk.UpdatedAt,
+			ApiKey:    fk.ApiKey,
+		})
+	case "feed_token":
+		ft := FeedToken{}
+		if err := json.Unmarshal(rec.Data, &ft); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, ft.UserID)
+		if err != nil {
+			return err
+		}
+		return q.RestoreFeedToken(ctx, database.RestoreFeedTokenParams{
+			UserID:    userID,
+			CreatedAt: ft.CreatedAt,
+			UpdatedAt: ft.UpdatedAt,
+			Token:     ft.Token,
+		})
+	case "webhook":
+		wh := Webhook{}
+		if err := json.Unmarshal(rec.Data, &wh); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, wh.UserID)
+		if err != nil {
+			return err
+		}
+		feedID := uuid.NullUUID{}
+		if wh.FeedID != nil {
+			feedID.UUID, err = r.lookup(rec.Type, *wh.FeedID)
+			if err != nil {
+				return err
+			}
+			feedID.Valid = true
+		}
+		return q.RestoreWebhook(ctx, database.RestoreWebhookParams{
+			ID:        uuid.New(),
+			CreatedAt: wh.CreatedAt,
+			UpdatedAt: wh.UpdatedAt,
+			UserID:    userID,
+			FeedID:    feedID,
+			Url:       wh.Url,
+			Secret:    wh.Secret,
+		})
+	case "rule":
+		ru := Rule{}
+		if err := json.Unmarshal(rec.Data, &ru); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, ru.UserID)
+		if err != nil {
+			return err
+		}
+		feedID := uuid.NullUUID{}
+		if ru.FeedID != nil {
+			feedID.UUID, err = r.lookup(rec.Type, *ru.FeedID)
+			if err != nil {
+				return err
+			}
+			feedID.Valid = true
+		}
+		return q.RestoreRule(ctx, database.RestoreRuleParams{
+			ID:           uuid.New(),
+			CreatedAt:    ru.CreatedAt,
+			UpdatedAt:    ru.UpdatedAt,
+			UserID:       userID,
+			FeedID:       feedID,
+			TitleRegex:   ru.TitleRegex,
+			ContentRegex: ru.ContentRegex,
+			Action:       ru.Action,
+		})
+	case "watch_word":
+		ww := WatchWord{}
+		if err := json.Unmarshal(rec.Data, &ww); err != nil {
+			return err
+		}
+		userID, err := r.lookup(rec.Type, ww.UserID)
+		if err != nil {
+			return err
+		}
+		id, err := q.RestoreWatchWord(ctx, database.RestoreWatchWordParams{
+			ID:             uuid.New(),
+			CreatedAt:      ww.CreatedAt,
+			UpdatedAt:      ww.UpdatedAt,
+			UserID:         userID,
+			Word:           ww.Word,
+			Regex:          ww.Regex,
+			NotifyWebhooks: ww.NotifyWebhooks,
+			NotifyEmail:    ww.NotifyEmail,
+		})
+		if err != nil {
+			return err
+		}
+		r.ids[ww.ID] = id
+	case "alert":
+		a := Alert{}
+		if err := json.Unmarshal(rec.Data, &a); err != nil {
+			return err
+		}
+		watchWordID, err := r.lookup(rec.Type, a.WatchWordID)
+		if err != nil {
+			return err
+		}
+		postID, err := r.lookup(rec.Type, a.PostID)
+		if err != nil {
+			return err
+		}
+		return q.RestoreAlert(ctx, database.RestoreAlertParams{
+			ID:          uuid.New(),
+			CreatedAt:   a.CreatedAt,
+			WatchWordID: watchWordID,
+			PostID:      postID,
+			Snippet:     a.Snippet,
+		})
+	default:
+		return fmt.Errorf("unknown record type '%s'", rec.Type)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Open opens link in the user's web browser. If $BROWSER is set, it's run
+// with the URL, replacing "%s" if it contains one, and Open waits for it to
+// exit, since it may be a terminal browser. Otherwise the platform's default
+// handler is started in the background. Links come from feeds, so only http
+// and https URLs are opened: the default handlers would also run local files.
+func Open(link string) error {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("won't open %q, only http and https URLs can be opened", link)
+	}
+	link = u.String()
+
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		// $BROWSER can list several browsers to try, separated by colons.
+		browser, _, _ = strings.Cut(browser, ":")
+		fields := strings.Fields(browser)
+		if len(fields) == 0 {
+			return fmt.Errorf("$BROWSER is empty")
+		}
+		args := fields[1:]
+		if strings.Contains(browser, "%s") {
+			for i, arg := range args {
+				args[i] = strings.ReplaceAll(arg, "%s", link)
+			}
+		} else {
+			args = append(args, link)
+		}
+		cmd := exec.Command(fields[0], args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("couldn't open a browser: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
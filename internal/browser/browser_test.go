@@ -0,0 +1,32 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenRejectsOtherSchemes(t *testing.T) {
+	// Nothing may run for these, even a $BROWSER.
+	t.Setenv("BROWSER", "false")
+	for _, link := range []string{
+		"",
+		"file:///etc/passwd",
+		"file://server/share/app.exe",
+		`\\server\share\app.exe`,
+		"C:\\Windows\\System32\\calc.exe",
+		"/usr/bin/xterm",
+		"-e xterm",
+		"--help",
+		"javascript:alert(1)",
+		"data:text/html,<script>alert(1)</script>",
+		"smb://server/share",
+		"https://",
+		"http:/example.com",
+		"https//example.com",
+	} {
+		err := Open(link)
+		if err == nil || !strings.Contains(err.Error(), "only http and https") {
+			t.Errorf("Open(%q) = %v, want it rejected", link, err)
+		}
+	}
+}
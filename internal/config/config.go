@@ -10,6 +10,24 @@ const configFileName = ".gatorconfig.json"
 type Config struct {
 	Db_url            string `json:"db_url"`
 	Current_user_name string `json:"current_user_name"`
+	Archive_dir       string `json:"archive_dir,omitempty"`
+	Smtp              *SMTP  `json:"smtp,omitempty"`
+}
+
+// SMTP is the mail server digests and alerts are sent through.
+type SMTP struct {
+	Host string `json:"host"`
+	// Port defaults to 587, or 465 if TLS is set.
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// TLS connects with TLS from the start instead of upgrading the
+	// connection with STARTTLS, which is used whenever the server offers it.
+	TLS  bool   `json:"tls,omitempty"`
+	From string `json:"from"`
+	// To is the address digests are sent to when their user has no address
+	// set and no other one is given. Alerts are only sent to their user's.
+	To string `json:"to,omitempty"`
 }
 
 func Read() (Config, error) {
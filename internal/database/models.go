@@ -11,6 +11,14 @@ import (
 	"github.com/google/uuid"
 )
 
+type Alert struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	WatchWordID uuid.UUID
+	PostID      uuid.UUID
+	Snippet     string
+}
+
 type Feed struct {
 	ID            uuid.UUID
 	CreatedAt     time.Time
@@ -19,6 +27,9 @@ type Feed struct {
 	Url           string
 	UserID        uuid.UUID
 	LastFetchedAt sql.NullTime
+	SiteUrl       string
+	FullText      bool
+	Seq           int64
 }
 
 type FeedFollow struct {
@@ -27,17 +38,97 @@ type FeedFollow struct {
 	UpdatedAt time.Time
 	UserID    uuid.UUID
 	FeedID    uuid.UUID
+	FolderID  uuid.NullUUID
+}
+
+type FeedToken struct {
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Token     string
+}
+
+type FeverKey struct {
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ApiKey    string
+}
+
+type Folder struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	UserID    uuid.UUID
+	Seq       int64
 }
 
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description string
-	PublishedAt time.Time
-	FeedID      uuid.UUID
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  string
+	PublishedAt  time.Time
+	FeedID       uuid.UUID
+	Content      string
+	SearchVector interface{}
+	Seq          int64
+}
+
+type PostArchive struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Format    string
+	Path      string
+}
+
+type PostHide struct {
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+}
+
+type PostRead struct {
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+}
+
+type PostStar struct {
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+}
+
+type PostTag struct {
+	CreatedAt time.Time
+	PostID    uuid.UUID
+	TagID     uuid.UUID
+}
+
+type Rule struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.UUID
+	FeedID       uuid.NullUUID
+	TitleRegex   string
+	ContentRegex string
+	Action       string
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	UserID    uuid.UUID
 }
 
 type User struct {
@@ -45,4 +136,40 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
+	Email     string
+}
+
+type WatchWord struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserID         uuid.UUID
+	Word           string
+	Regex          bool
+	NotifyWebhooks bool
+	NotifyEmail    bool
+}
+
+type Webhook struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Url       string
+	Secret    string
+}
+
+type WebhookDelivery struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	WebhookID     uuid.UUID
+	Event         string
+	Payload       string
+	Attempts      int32
+	StatusCode    sql.NullInt32
+	LastError     string
+	NextAttemptAt sql.NullTime
+	DeliveredAt   sql.NullTime
 }
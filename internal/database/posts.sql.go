@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,7 +25,7 @@ VALUES (
     $7,
     $8
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, content, search_vector, seq
 `
 
 type CreatePostParams struct {
@@ -59,12 +60,40 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Content,
+		&i.SearchVector,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const getPost = `-- name: GetPost :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, search_vector, seq
+FROM posts
+WHERE id = $1
+`
+
+func (q *Queries) GetPost(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.SearchVector,
+		&i.Seq,
 	)
 	return i, err
 }
 
 const getPosts = `-- name: GetPosts :many
-SELECT id, created_at, updated_at, title, url, description, published_at, feed_id
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, search_vector, seq
 FROM posts
 LIMIT $1
 `
@@ -87,6 +116,9 @@ func (q *Queries) GetPosts(ctx context.Context, limit int32) ([]Post, error) {
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.SearchVector,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -102,29 +134,299 @@ func (q *Queries) GetPosts(ctx context.Context, limit int32) ([]Post, error) {
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id
+SELECT
+    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.seq,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = $1
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = $1
+        AND post_stars.post_id = posts.id
+    ) AS starred
 FROM posts
 JOIN feed_follows
 ON posts.feed_id = feed_follows.feed_id
+JOIN feeds
+ON posts.feed_id = feeds.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
 WHERE feed_follows.user_id = $1
-ORDER BY published_at DESC
-LIMIT $2
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = $1
+    AND post_hides.post_id = posts.id
+)
+AND ($2::text IS NULL OR feeds.url = $2 OR feeds.name = $2)
+AND ($3::text IS NULL OR folders.name = $3)
+AND (
+    $4::text IS NULL
+    OR posts.id IN (
+        SELECT post_tags.post_id
+        FROM post_tags
+        JOIN tags
+        ON post_tags.tag_id = tags.id
+        WHERE tags.user_id = $1
+        AND tags.name = $4
+    )
+)
+AND ($5::timestamp IS NULL OR CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END >= $5)
+AND ($7::timestamp IS NULL OR CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END < $7)
+AND (
+    $8::text IS NULL
+    OR posts.title ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.description ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.content ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+)
+AND (
+    NOT $9::boolean
+    OR NOT EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = $1
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (
+    NOT $10::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = $1
+        AND post_stars.post_id = posts.id
+    )
+)
+AND (
+    $11::uuid IS NULL
+    OR (CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END, posts.id) < (
+        SELECT CASE WHEN $6::text = 'fetched' THEN c.created_at ELSE c.published_at END, c.id
+        FROM posts c
+        WHERE c.id = $11
+    )
+)
+ORDER BY CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END DESC, posts.id DESC
+LIMIT $12
+OFFSET $13
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID      uuid.UUID
+	Feed        sql.NullString
+	Folder      sql.NullString
+	Tag         sql.NullString
+	From        sql.NullTime
+	Sort        string
+	To          sql.NullTime
+	Search      sql.NullString
+	UnreadOnly  bool
+	StarredOnly bool
+	Before      uuid.NullUUID
+	Limit       int32
+	Offset      int32
+}
+
+type GetPostsForUserRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  string
+	PublishedAt  time.Time
+	FeedID       uuid.UUID
+	Content      string
+	SearchVector interface{}
+	Seq          int64
+	Read         bool
+	Starred      bool
 }
 
-func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.Feed,
+		arg.Folder,
+		arg.Tag,
+		arg.From,
+		arg.Sort,
+		arg.To,
+		arg.Search,
+		arg.UnreadOnly,
+		arg.StarredOnly,
+		arg.Before,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []GetPostsForUserRow
 	for rows.Next() {
-		var i Post
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.SearchVector,
+			&i.Seq,
+			&i.Read,
+			&i.Starred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserAfter = `-- name: GetPostsForUserAfter :many
+SELECT
+    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.seq,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = $1
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = $1
+        AND post_stars.post_id = posts.id
+    ) AS starred
+FROM posts
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+JOIN feeds
+ON posts.feed_id = feeds.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = $1
+    AND post_hides.post_id = posts.id
+)
+AND ($2::text IS NULL OR feeds.url = $2 OR feeds.name = $2)
+AND ($3::text IS NULL OR folders.name = $3)
+AND (
+    $4::text IS NULL
+    OR posts.id IN (
+        SELECT post_tags.post_id
+        FROM post_tags
+        JOIN tags
+        ON post_tags.tag_id = tags.id
+        WHERE tags.user_id = $1
+        AND tags.name = $4
+    )
+)
+AND ($5::timestamp IS NULL OR CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END >= $5)
+AND ($7::timestamp IS NULL OR CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END < $7)
+AND (
+    $8::text IS NULL
+    OR posts.title ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.description ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.content ILIKE '%' || replace(replace(replace($8, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+)
+AND (
+    NOT $9::boolean
+    OR NOT EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = $1
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (
+    NOT $10::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = $1
+        AND post_stars.post_id = posts.id
+    )
+)
+AND (CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END, posts.id) > (
+    SELECT CASE WHEN $6::text = 'fetched' THEN c.created_at ELSE c.published_at END, c.id
+    FROM posts c
+    WHERE c.id = $11
+)
+ORDER BY CASE WHEN $6::text = 'fetched' THEN posts.created_at ELSE posts.published_at END ASC, posts.id ASC
+LIMIT $12
+`
+
+type GetPostsForUserAfterParams struct {
+	UserID      uuid.UUID
+	Feed        sql.NullString
+	Folder      sql.NullString
+	Tag         sql.NullString
+	From        sql.NullTime
+	Sort        string
+	To          sql.NullTime
+	Search      sql.NullString
+	UnreadOnly  bool
+	StarredOnly bool
+	After       uuid.UUID
+	Limit       int32
+}
+
+type GetPostsForUserAfterRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  string
+	PublishedAt  time.Time
+	FeedID       uuid.UUID
+	Content      string
+	SearchVector interface{}
+	Seq          int64
+	Read         bool
+	Starred      bool
+}
+
+func (q *Queries) GetPostsForUserAfter(ctx context.Context, arg GetPostsForUserAfterParams) ([]GetPostsForUserAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserAfter,
+		arg.UserID,
+		arg.Feed,
+		arg.Folder,
+		arg.Tag,
+		arg.From,
+		arg.Sort,
+		arg.To,
+		arg.Search,
+		arg.UnreadOnly,
+		arg.StarredOnly,
+		arg.After,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserAfterRow
+	for rows.Next() {
+		var i GetPostsForUserAfterRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -134,6 +436,11 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.SearchVector,
+			&i.Seq,
+			&i.Read,
+			&i.Starred,
 		); err != nil {
 			return nil, err
 		}
@@ -147,3 +454,124 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT
+    matches.id,
+    matches.title,
+    matches.url,
+    matches.published_at,
+    matches.feed_name,
+    matches.rank,
+    ts_headline(
+        'english',
+        matches.title || ' ' || regexp_replace(matches.description, '<[^>]*>', ' ', 'g'),
+        websearch_to_tsquery('english', $1::text),
+        'StartSel=**, StopSel=**, MaxWords=35, MinWords=15, MaxFragments=2'
+    ) AS snippet
+FROM (
+    SELECT
+        posts.id,
+        posts.title,
+        posts.url,
+        posts.published_at,
+        posts.description,
+        feeds.name AS feed_name,
+        ts_rank(posts.search_vector, websearch_to_tsquery('english', $1::text)) AS rank
+    FROM posts
+    JOIN feeds
+    ON posts.feed_id = feeds.id
+    WHERE posts.search_vector @@ websearch_to_tsquery('english', $1::text)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_hides
+        WHERE post_hides.user_id = $2
+        AND post_hides.post_id = posts.id
+    )
+    AND (
+        $3::boolean
+        OR posts.feed_id IN (
+            SELECT feed_follows.feed_id
+            FROM feed_follows
+            LEFT JOIN folders
+            ON feed_follows.folder_id = folders.id
+            WHERE feed_follows.user_id = $2
+            AND ($4::text IS NULL OR folders.name = $4)
+        )
+    )
+    ORDER BY rank DESC, posts.published_at DESC, posts.id DESC
+    LIMIT $5
+) AS matches
+ORDER BY matches.rank DESC, matches.published_at DESC, matches.id DESC
+`
+
+type SearchPostsParams struct {
+	Query    string
+	UserID   uuid.UUID
+	AllFeeds bool
+	Folder   sql.NullString
+	Limit    int32
+}
+
+type SearchPostsRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	PublishedAt time.Time
+	FeedName    string
+	Rank        float32
+	Snippet     string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.UserID,
+		arg.AllFeeds,
+		arg.Folder,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostContent = `-- name: SetPostContent :exec
+UPDATE posts
+SET updated_at = NOW(), content = $2
+WHERE id = $1
+`
+
+type SetPostContentParams struct {
+	ID      uuid.UUID
+	Content string
+}
+
+func (q *Queries) SetPostContent(ctx context.Context, arg SetPostContentParams) error {
+	_, err := q.db.ExecContext(ctx, setPostContent, arg.ID, arg.Content)
+	return err
+}
@@ -0,0 +1,34 @@
+// Package dates parses the dates users filter posts by. Posts' times are
+// stored as local time without a time zone, so dates without one are read as
+// local time, and dates with one are converted to it.
+package dates
+
+import (
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// Start parses the start of a date range, in any format dateparse knows.
+func Start(s string) (time.Time, error) {
+	t, err := dateparse.ParseIn(s, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(time.Local), nil
+}
+
+// End parses the end of a date range, which the range ends before. An end
+// that's only a day, like 2025-01-10, is moved to the start of the next day,
+// so that the range includes that day.
+func End(s string) (time.Time, error) {
+	t, err := Start(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !strings.Contains(s, ":") && t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,44 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartAndEnd(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	defer func(l *time.Location) { time.Local = l }(time.Local)
+	time.Local = local
+
+	tests := []struct {
+		in    string
+		start time.Time
+		end   time.Time
+	}{
+		{"2025-01-10", time.Date(2025, 1, 10, 0, 0, 0, 0, local), time.Date(2025, 1, 11, 0, 0, 0, 0, local)},
+		{"Jan 10, 2025", time.Date(2025, 1, 10, 0, 0, 0, 0, local), time.Date(2025, 1, 11, 0, 0, 0, 0, local)},
+		{"2025-01-10 15:30", time.Date(2025, 1, 10, 15, 30, 0, 0, local), time.Date(2025, 1, 10, 15, 30, 0, 0, local)},
+		{"2025-01-10 00:00:00", time.Date(2025, 1, 10, 0, 0, 0, 0, local), time.Date(2025, 1, 10, 0, 0, 0, 0, local)},
+		{"2025-01-10T12:00:00Z", time.Date(2025, 1, 10, 14, 0, 0, 0, local), time.Date(2025, 1, 10, 14, 0, 0, 0, local)},
+	}
+	for _, tt := range tests {
+		start, err := Start(tt.in)
+		if err != nil {
+			t.Fatalf("Start(%q): %v", tt.in, err)
+		}
+		end, err := End(tt.in)
+		if err != nil {
+			t.Fatalf("End(%q): %v", tt.in, err)
+		}
+		if !start.Equal(tt.start) || start.Location() != local {
+			t.Errorf("Start(%q) = %v, want %v", tt.in, start, tt.start)
+		}
+		if !end.Equal(tt.end) || end.Location() != local {
+			t.Errorf("End(%q) = %v, want %v", tt.in, end, tt.end)
+		}
+	}
+
+	if _, err := Start("someday"); err == nil {
+		t.Error("Start(\"someday\") didn't fail")
+	}
+}
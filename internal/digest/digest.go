@@ -0,0 +1,171 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	textTemplate = template.Must(template.New("digest.txt").Funcs(template.FuncMap{
+		"date": formatDate,
+	}).ParseFS(templates, "templates/digest.txt"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(htmltemplate.FuncMap{
+		"date": formatDate,
+	}).ParseFS(templates, "templates/digest.html"))
+)
+
+// Digest is a user's unread posts since a time, grouped by folder and feed.
+type Digest struct {
+	User   string
+	Since  time.Time
+	Groups []Group
+}
+
+// Group is the feeds in a folder, or the ones in no folder if Folder is
+// empty.
+type Group struct {
+	Folder string
+	Feeds  []Feed
+}
+
+type Feed struct {
+	Name  string
+	Posts []Post
+}
+
+type Post struct {
+	Title     string
+	URL       string
+	Published time.Time
+	// Summary is the start of the post's description, as plain text.
+	Summary string
+}
+
+// Count returns the number of posts in the digest.
+func (d Digest) Count() int {
+	n := 0
+	for _, g := range d.Groups {
+		for _, f := range g.Feeds {
+			n += len(f.Posts)
+		}
+	}
+	return n
+}
+
+// Subject is the subject of the digest's email.
+func (d Digest) Subject() string {
+	if d.Count() == 1 {
+		return "Gator digest: 1 unread post"
+	}
+	return fmt.Sprintf("Gator digest: %d unread posts", d.Count())
+}
+
+// Message returns the digest as an email from from to to, with a plain text
+// and an HTML version.
+func Message(d Digest, from, to string) ([]byte, error) {
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplate.Execute(&html, d); err != nil {
+		return nil, err
+	}
+	return message(from, to, d.Subject(), []part{
+		{"text/plain; charset=utf-8", text.Bytes()},
+		{"text/html; charset=utf-8", html.Bytes()},
+	})
+}
+
+// TextMessage returns a plain text email from from to to.
+func TextMessage(from, to, subject string, text []byte) ([]byte, error) {
+	return message(from, to, subject, []part{{"text/plain; charset=utf-8", text}})
+}
+
+// part is one version of an email's body.
+type part struct {
+	contentType string
+	content     []byte
+}
+
+// message returns an email with the parts as alternative versions of its
+// body.
+func message(from, to, subject string, parts []part) ([]byte, error) {
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address '%s': %w", from, err)
+	}
+	toAddr, err := mail.ParseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address '%s': %w", to, err)
+	}
+
+	var msg bytes.Buffer
+	body := multipart.NewWriter(&msg)
+	header := []string{
+		"From: " + fromAddr.String(),
+		"To: " + toAddr.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(fromAddr.Address),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + body.Boundary(),
+	}
+	msg.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, part := range parts {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(crlf(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// messageID returns a random message ID on the domain of the address.
+func messageID(address string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	_, domain, _ := strings.Cut(address, "@")
+	if domain == "" {
+		domain = "localhost"
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// crlf ends the lines of b with CRLF, as email requires.
+func crlf(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n"))
+}
+
+func formatDate(t time.Time) string {
+	return t.Local().Format("Jan 2, 2006 15:04")
+}
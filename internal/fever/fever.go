@@ -0,0 +1,389 @@
+package fever
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/sanitize"
+	"github.com/google/uuid"
+)
+
+const (
+	apiVersion = 3
+	// itemsPageSize is the number of items returned at once, as in Fever.
+	itemsPageSize = 50
+)
+
+// Key returns the Fever API key of a user with the given password: the MD5
+// hash of "name:password", which clients compute from what users type in.
+func Key(name, password string) string {
+	sum := md5.Sum([]byte(name + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// Server implements the Fever API (https://feedafever.com/api) for the users
+// with a Fever key, so that mobile apps that speak it can read their feeds.
+// Folders are Fever groups, and feeds, folders and posts are identified by
+// their sequence numbers, since Fever uses integer IDs. Only JSON responses
+// are supported.
+type Server struct {
+	q *database.Queries
+}
+
+func New(q *database.Queries) *Server {
+	return &Server{q: q}
+}
+
+type group struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type feedsGroup struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+type feed struct {
+	ID                int64  `json:"id"`
+	FaviconID         int64  `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+type item struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	res := map[string]any{"api_version": apiVersion, "auth": 0}
+
+	user, err := s.q.GetUserByFeverKey(r.Context(), strings.ToLower(r.Form.Get("api_key")))
+	if err == sql.ErrNoRows {
+		writeJSON(w, res)
+		return
+	}
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	res["auth"] = 1
+
+	if err := s.handle(r, user, res); err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+// handle marks items read or saved, then adds what the request asks for to
+// res. Requests can ask for several things at once.
+func (s *Server) handle(r *http.Request, user database.User, res map[string]any) error {
+	ctx := r.Context()
+
+	if r.Form.Has("mark") {
+		if err := s.mark(r, user, res); err != nil {
+			return err
+		}
+	}
+
+	feedFollows, err := s.q.GetFeedFollowsForUser(ctx, user.Name)
+	if err != nil {
+		return err
+	}
+	allFeeds, err := s.q.GetFeeds(ctx)
+	if err != nil {
+		return err
+	}
+	feeds := map[uuid.UUID]database.Feed{}
+	lastRefreshed := int64(0)
+	for _, f := range allFeeds {
+		feeds[f.ID] = f
+	}
+	for _, ff := range feedFollows {
+		if f := feeds[ff.FeedID]; f.LastFetchedAt.Valid {
+			lastRefreshed = max(lastRefreshed, f.LastFetchedAt.Time.Unix())
+		}
+	}
+	res["last_refreshed_on_time"] = lastRefreshed
+
+	if r.Form.Has("groups") || r.Form.Has("feeds") {
+		groups, feedsGroups, err := s.groups(r, user, feedFollows, feeds)
+		if err != nil {
+			return err
+		}
+		if r.Form.Has("groups") {
+			res["groups"] = groups
+		}
+		res["feeds_groups"] = feedsGroups
+	}
+
+	if r.Form.Has("feeds") {
+		list := []feed{}
+		for _, ff := range feedFollows {
+			f := feeds[ff.FeedID]
+			updated := int64(0)
+			if f.LastFetchedAt.Valid {
+				updated = f.LastFetchedAt.Time.Unix()
+			}
+			list = append(list, feed{
+				ID:                f.Seq,
+				Title:             ff.FeedName,
+				URL:               f.Url,
+				SiteURL:           f.SiteUrl,
+				LastUpdatedOnTime: updated,
+			})
+		}
+		res["feeds"] = list
+	}
+
+	if r.Form.Has("favicons") {
+		res["favicons"] = []any{}
+	}
+	if r.Form.Has("links") {
+		res["links"] = []any{}
+	}
+
+	if r.Form.Has("items") {
+		items, total, err := s.items(r, user, feeds)
+		if err != nil {
+			return err
+		}
+		res["items"] = items
+		res["total_items"] = total
+	}
+
+	if r.Form.Has("unread_item_ids") {
+		if err := s.unreadItemIDs(r, user, res); err != nil {
+			return err
+		}
+	}
+	if r.Form.Has("saved_item_ids") {
+		if err := s.savedItemIDs(r, user, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groups lists the user's folders, and which of their feeds are in each.
+func (s *Server) groups(r *http.Request, user database.User, feedFollows []database.GetFeedFollowsForUserRow, feeds map[uuid.UUID]database.Feed) ([]group, []feedsGroup, error) {
+	folders, err := s.q.GetFoldersForUser(r.Context(), user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	groups := []group{}
+	feedIDs := map[uuid.UUID][]string{}
+	for _, ff := range feedFollows {
+		if ff.FolderID.Valid {
+			feedIDs[ff.FolderID.UUID] = append(feedIDs[ff.FolderID.UUID], strconv.FormatInt(feeds[ff.FeedID].Seq, 10))
+		}
+	}
+	feedsGroups := []feedsGroup{}
+	for _, folder := range folders {
+		groups = append(groups, group{folder.Seq, folder.Name})
+		if ids := feedIDs[folder.ID]; len(ids) > 0 {
+			feedsGroups = append(feedsGroups, feedsGroup{folder.Seq, strings.Join(ids, ",")})
+		}
+	}
+	return groups, feedsGroups, nil
+}
+
+// items lists up to itemsPageSize posts: the ones after since_id, oldest
+// first, the ones before max_id, newest first, or the ones in with_ids.
+func (s *Server) items(r *http.Request, user database.User, feeds map[uuid.UUID]database.Feed) ([]item, int64, error) {
+	params := database.GetFeverItemsParams{
+		UserID: user.ID,
+		Limit:  itemsPageSize,
+	}
+	if v, err := strconv.ParseInt(r.Form.Get("since_id"), 10, 64); err == nil {
+		params.SinceID = sql.NullInt64{Int64: v, Valid: true}
+	}
+	if v, err := strconv.ParseInt(r.Form.Get("max_id"), 10, 64); err == nil && v > 0 {
+		params.MaxID = sql.NullInt64{Int64: v, Valid: true}
+	}
+	if r.Form.Has("with_ids") {
+		params.WithIds = parseIDs(r.Form.Get("with_ids"))
+		if len(params.WithIds) > itemsPageSize {
+			params.WithIds = params.WithIds[:itemsPageSize]
+		}
+		if params.WithIds == nil {
+			params.WithIds = []int64{}
+		}
+	}
+
+	posts, err := s.q.GetFeverItems(r.Context(), params)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.q.CountFeverItems(r.Context(), user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := []item{}
+	for _, p := range posts {
+		content := p.Content
+		if content == "" {
+			content = p.Description
+		}
+		body, err := sanitize.HTML(content, p.Url)
+		if err != nil {
+			body = ""
+		}
+		items = append(items, item{
+			ID:            p.Seq,
+			FeedID:        feeds[p.FeedID].Seq,
+			Title:         p.Title,
+			HTML:          body,
+			URL:           p.Url,
+			IsSaved:       boolInt(p.Starred),
+			IsRead:        boolInt(p.Read),
+			CreatedOnTime: p.PublishedAt.Unix(),
+		})
+	}
+	return items, total, nil
+}
+
+func (s *Server) unreadItemIDs(r *http.Request, user database.User, res map[string]any) error {
+	ids, err := s.q.GetUnreadPostSeqs(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	res["unread_item_ids"] = joinIDs(ids)
+	return nil
+}
+
+func (s *Server) savedItemIDs(r *http.Request, user database.User, res map[string]any) error {
+	ids, err := s.q.GetStarredPostSeqs(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	res["saved_item_ids"] = joinIDs(ids)
+	return nil
+}
+
+// mark marks an item read, unread, saved or unsaved, or all the items of a
+// feed or group read up to the time in before. Group 0 is all feeds.
+func (s *Server) mark(r *http.Request, user database.User, res map[string]any) error {
+	ctx := r.Context()
+	id, err := strconv.ParseInt(r.Form.Get("id"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	as := r.Form.Get("as")
+
+	switch r.Form.Get("mark") {
+	case "item":
+		post, err := s.q.GetPostBySeq(ctx, id)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch as {
+		case "read":
+			err = s.q.MarkPostRead(ctx, database.MarkPostReadParams{CreatedAt: time.Now(), UserID: user.ID, PostID: post.ID})
+		case "unread":
+			err = s.q.MarkPostUnread(ctx, database.MarkPostUnreadParams{UserID: user.ID, PostID: post.ID})
+		case "saved":
+			err = s.q.StarPost(ctx, database.StarPostParams{CreatedAt: time.Now(), UserID: user.ID, PostID: post.ID})
+		case "unsaved":
+			err = s.q.UnstarPost(ctx, database.UnstarPostParams{UserID: user.ID, PostID: post.ID})
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if as == "saved" || as == "unsaved" {
+			return s.savedItemIDs(r, user, res)
+		}
+		return s.unreadItemIDs(r, user, res)
+
+	case "feed", "group":
+		if as != "read" {
+			return nil
+		}
+		params := database.MarkFeedsReadBeforeParams{
+			CreatedAt: time.Now(),
+			UserID:    user.ID,
+			Before:    time.Now(),
+		}
+		if before, err := strconv.ParseInt(r.Form.Get("before"), 10, 64); err == nil && before > 0 {
+			params.Before = time.Unix(before, 0)
+		}
+		if r.Form.Get("mark") == "feed" {
+			params.FeedSeq = sql.NullInt64{Int64: id, Valid: true}
+		} else if id > 0 {
+			params.FolderSeq = sql.NullInt64{Int64: id, Valid: true}
+		} else if id < 0 {
+			// Group -1 is Fever's sparks, which gator doesn't have.
+			return nil
+		}
+		if err := s.q.MarkFeedsReadBefore(ctx, params); err != nil {
+			return err
+		}
+		return s.unreadItemIDs(r, user, res)
+	}
+	return nil
+}
+
+func (s *Server) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%s %s: %v", r.Method, r.URL.RequestURI(), err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseIDs(s string) []int64 {
+	var ids []int64
+	for _, field := range strings.Split(s, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func joinIDs(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(s, ",")
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+package greader
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/fever"
+)
+
+// Stream IDs of the states and labels posts can have.
+const (
+	readingList  = "user/-/state/com.google/reading-list"
+	readState    = "user/-/state/com.google/read"
+	starredState = "user/-/state/com.google/starred"
+	keptUnread   = "user/-/state/com.google/kept-unread"
+	labelPrefix  = "user/-/label/"
+	feedPrefix   = "feed/"
+)
+
+// Server implements the Google Reader API used by apps like NetNewsWire,
+// FeedMe and Readrops, under /greader/. Users log in with their name and the
+// password they set with the fever command. Folders are labels, feeds are
+// identified by their URL, and posts by their sequence number.
+type Server struct {
+	q   *database.Queries
+	mux *http.ServeMux
+}
+
+func New(q *database.Queries) *Server {
+	s := &Server{q: q, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("/greader/accounts/ClientLogin", s.clientLogin)
+	s.handle("GET /token", s.token)
+	s.handle("GET /user-info", s.userInfo)
+	s.handle("GET /subscription/list", s.subscriptionList)
+	s.handle("POST /subscription/edit", s.subscriptionEdit)
+	s.handle("POST /subscription/quickadd", s.quickAdd)
+	s.handle("GET /tag/list", s.tagList)
+	s.handle("POST /rename-tag", s.renameTag)
+	s.handle("POST /disable-tag", s.disableTag)
+	s.handle("GET /unread-count", s.unreadCount)
+	s.handle("GET /stream/items/ids", s.itemIDs)
+	s.handle("/stream/items/contents", s.itemContents)
+	s.handle("GET /stream/contents", s.streamContents)
+	s.handle("GET /stream/contents/{stream...}", s.streamContents)
+	s.handle("POST /edit-tag", s.editTag)
+	s.handle("POST /mark-all-as-read", s.markAllAsRead)
+	s.mux.HandleFunc("/greader/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, user database.User) error
+
+// handle registers an API handler, which is only called for logged in users.
+// Errors it returns are logged and reported without details.
+func (s *Server) handle(pattern string, handler handlerFunc) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "", pattern
+	}
+	pattern = strings.TrimSpace(method + " /greader/reader/api/0" + path)
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		user, err := s.authenticate(r)
+		if err == sql.ErrNoRows {
+			w.Header().Set("Google-Bad-Token", "true")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			internalError(w, r, err)
+			return
+		}
+		if err := handler(w, r, user); err != nil {
+			internalError(w, r, err)
+		}
+	})
+}
+
+// authToken returns the token a user logs in with: their name and a hash of
+// their Fever key, so it's valid until they change their password.
+func authToken(name, key string) string {
+	sum := sha256.Sum256([]byte("greader:" + key))
+	return name + "/" + hex.EncodeToString(sum[:])
+}
+
+// authenticate returns the user whose token is in the request's
+// "Authorization: GoogleLogin auth=" header, or sql.ErrNoRows if there's no
+// valid token.
+func (s *Server) authenticate(r *http.Request) (database.User, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "GoogleLogin auth=")
+	if !ok {
+		return database.User{}, sql.ErrNoRows
+	}
+	name, _, _ := strings.Cut(token, "/")
+	user, err := s.q.GetUser(r.Context(), name)
+	if err != nil {
+		return user, err
+	}
+	key, err := s.q.GetFeverKey(r.Context(), user.ID)
+	if err != nil {
+		return user, err
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(authToken(user.Name, key.ApiKey))) != 1 {
+		return user, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (s *Server) clientLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	name := r.Form.Get("Email")
+	user, err := s.q.GetUser(r.Context(), name)
+	if err != nil && err != sql.ErrNoRows {
+		internalError(w, r, err)
+		return
+	}
+	key := database.FeverKey{}
+	if err == nil {
+		key, err = s.q.GetFeverKey(r.Context(), user.ID)
+		if err != nil && err != sql.ErrNoRows {
+			internalError(w, r, err)
+			return
+		}
+	}
+	if err != nil || subtle.ConstantTimeCompare([]byte(fever.Key(name, r.Form.Get("Passwd"))), []byte(key.ApiKey)) != 1 {
+		http.Error(w, "Error=BadAuthentication", http.StatusUnauthorized)
+		return
+	}
+
+	token := authToken(user.Name, key.ApiKey)
+	if r.Form.Get("output") == "json" {
+		writeJSON(w, map[string]string{"SID": token, "LSID": "null", "Auth": token})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "SID=%s\nLSID=null\nAuth=%s\n", token, token)
+}
+
+// token returns the token apps send along with edits. Requests are already
+// authenticated by their header, so it isn't checked.
+func (s *Server) token(w http.ResponseWriter, r *http.Request, user database.User) error {
+	sum := sha256.Sum256([]byte("token:" + user.ID.String()))
+	writeText(w, hex.EncodeToString(sum[:])[:57])
+	return nil
+}
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request, user database.User) error {
+	writeJSON(w, map[string]string{
+		"userId":        user.ID.String(),
+		"userName":      user.Name,
+		"userProfileId": user.ID.String(),
+		"userEmail":     "",
+	})
+	return nil
+}
+
+func internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeText(w http.ResponseWriter, s string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, s)
+}
+
+// normalizeStream replaces the user ID in the stream IDs of states and labels
+// with -, which stands for the logged in user.
+func normalizeStream(id string) string {
+	rest, ok := strings.CutPrefix(id, "user/")
+	if !ok {
+		return id
+	}
+	_, rest, ok = strings.Cut(rest, "/")
+	if !ok {
+		return id
+	}
+	return "user/-/" + rest
+}
+
+// feedURL returns the URL of a feed's stream ID. Paths can't contain "//"
+// unescaped, so it's restored after a scheme if an app didn't escape it.
+func feedURL(streamID string) (string, bool) {
+	url, ok := strings.CutPrefix(streamID, feedPrefix)
+	if ok && !strings.Contains(url, "://") {
+		url = strings.Replace(url, ":/", "://", 1)
+	}
+	return url, ok
+}
@@ -0,0 +1,312 @@
+package greader_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/dbtest"
+	"github.com/R0Xps/gatorcli/internal/fever"
+	"github.com/R0Xps/gatorcli/internal/greader"
+	"github.com/google/uuid"
+)
+
+func TestMain(m *testing.M) {
+	// Timestamps are stored without a time zone, so the times in the
+	// fixtures only hold if the tests run in UTC.
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// exchange is a request recorded in a fixture, and the response expected for
+// it.
+type exchange struct {
+	line       int
+	method     string
+	target     string
+	header     http.Header
+	body       string
+	status     int
+	wantHeader http.Header
+	wantBody   string
+	// checkBody is false for responses without a body in the fixture.
+	checkBody bool
+}
+
+// readExchanges reads the exchanges of a fixture in testdata, which look like
+// curl -v's output:
+//
+//	# A comment.
+//	> POST /greader/accounts/ClientLogin
+//	> Content-Type: application/x-www-form-urlencoded
+//	>
+//	> Email=alice&Passwd=correct-horse
+//	< 200
+//	< Content-Type: text/plain; charset=utf-8
+//	<
+//	< SID=...
+//
+// The lines after a lone ">" or "<" are the body. Only the response headers
+// in the fixture are checked, and the response body isn't checked if the
+// fixture has none.
+func readExchanges(file string) ([]exchange, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var exchanges []exchange
+	var ex *exchange
+	inBody := false
+	var body []string
+	// flush ends the request or response being read.
+	flush := func() {
+		if ex == nil {
+			return
+		}
+		if ex.status == 0 {
+			ex.body = strings.Join(body, "\n")
+		} else {
+			ex.wantBody = strings.Join(body, "\n")
+			ex.checkBody = inBody
+		}
+		inBody, body = false, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mark, text := line[:1], strings.TrimPrefix(line[1:], " ")
+		if mark != ">" && mark != "<" {
+			return nil, fmt.Errorf("%s:%d: lines start with >, < or #", file, n)
+		}
+
+		switch {
+		case mark == ">" && (ex == nil || ex.status != 0):
+			// A new request.
+			flush()
+			method, target, ok := strings.Cut(text, " ")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: expected a method and a path", file, n)
+			}
+			exchanges = append(exchanges, exchange{line: n, method: method, target: target, header: http.Header{}, wantHeader: http.Header{}})
+			ex = &exchanges[len(exchanges)-1]
+		case mark == "<" && ex != nil && ex.status == 0:
+			// The response to the request.
+			flush()
+			status, err := strconv.Atoi(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: expected a status code", file, n)
+			}
+			ex.status = status
+		case ex == nil:
+			return nil, fmt.Errorf("%s:%d: expected a request first", file, n)
+		case inBody:
+			body = append(body, text)
+		case text == "":
+			inBody = true
+		default:
+			name, value, ok := strings.Cut(text, ": ")
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: expected a header, or a lone %s before the body", file, n, mark)
+			}
+			if mark == ">" {
+				ex.header.Add(name, value)
+			} else {
+				ex.wantHeader.Add(name, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	if ex != nil && ex.status == 0 {
+		return nil, fmt.Errorf("%s:%d: request without a response", file, ex.line)
+	}
+	return exchanges, nil
+}
+
+// replay sends the exchange's request to srv and checks the response.
+func (ex exchange) replay(t *testing.T, srv *httptest.Server, file string) {
+	t.Helper()
+	at := fmt.Sprintf("%s:%d: %s %s", file, ex.line, ex.method, ex.target)
+
+	var body io.Reader
+	if ex.body != "" {
+		body = strings.NewReader(ex.body)
+	}
+	req, err := http.NewRequest(ex.method, srv.URL+ex.target, body)
+	if err != nil {
+		t.Fatalf("%s: %v", at, err)
+	}
+	req.Header = ex.header
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s: %v", at, err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("%s: %v", at, err)
+	}
+	got := strings.TrimRight(string(b), "\n")
+
+	if res.StatusCode != ex.status {
+		t.Fatalf("%s: got %d %s, want %d", at, res.StatusCode, got, ex.status)
+	}
+	for name := range ex.wantHeader {
+		if res.Header.Get(name) != ex.wantHeader.Get(name) {
+			t.Errorf("%s: got %s %q, want %q", at, name, res.Header.Get(name), ex.wantHeader.Get(name))
+		}
+	}
+
+	if !ex.checkBody {
+		return
+	}
+	if !strings.HasPrefix(ex.wantBody, "{") {
+		if got != ex.wantBody {
+			t.Errorf("%s: got body\n%s\nwant\n%s", at, got, ex.wantBody)
+		}
+		return
+	}
+	var gotJSON, wantJSON any
+	if err := json.Unmarshal([]byte(ex.wantBody), &wantJSON); err != nil {
+		t.Fatalf("%s: invalid JSON in the fixture: %v", at, err)
+	}
+	if err := json.Unmarshal(b, &gotJSON); err != nil {
+		t.Fatalf("%s: got invalid JSON %s: %v", at, got, err)
+	}
+	if !contains(gotJSON, wantJSON) {
+		t.Errorf("%s: got body\n%s\nwant it to contain\n%s", at, got, ex.wantBody)
+	}
+}
+
+// contains reports whether the JSON value got has everything in want.
+// Objects may have fields want leaves out, and a null in want also matches a
+// missing field, but arrays have to be the same length.
+func contains(got, want any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		got, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for name, w := range want {
+			g, found := got[name]
+			if w == nil {
+				if found && g != nil {
+					return false
+				}
+				continue
+			}
+			if !found || !contains(g, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		got, ok := got.([]any)
+		if !ok || len(got) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !contains(got[i], want[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
+
+// seed fills a test database for the fixtures. alice, whose password is
+// correct-horse, follows Go Blog in her Tech folder, and Example. bob, who
+// hasn't turned the API on, follows Bob's Feed. Each feed has two posts, except
+// Bob's Feed which has one, and posts are numbered 1 to 5 in that order.
+func seed(t *testing.T) *database.Queries {
+	t.Helper()
+	ctx := context.Background()
+	q := dbtest.New(t)
+
+	alice := dbtest.User(t, q, "alice")
+	bob := dbtest.User(t, q, "bob")
+	err := q.SetFeverKey(ctx, database.SetFeverKeyParams{
+		UserID:    alice.ID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		ApiKey:    fever.Key("alice", "correct-horse"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goBlog := dbtest.Feed(t, q, alice, "Go Blog", "https://go.dev/blog/feed.atom")
+	example := dbtest.Feed(t, q, alice, "Example", "https://example.com/feed.xml")
+	bobs := dbtest.Feed(t, q, bob, "Bob's Feed", "https://bob.example/feed.xml")
+	dbtest.Posts(t, q, goBlog, 2, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC))
+	dbtest.Posts(t, q, example, 2, time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC))
+	dbtest.Posts(t, q, bobs, 1, time.Date(2025, 1, 12, 12, 0, 0, 0, time.UTC))
+
+	tech, err := q.CreateFolder(ctx, database.CreateFolderParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      "Tech",
+		UserID:    alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.SetFeedFollowFolder(ctx, database.SetFeedFollowFolderParams{
+		UserID:   alice.ID,
+		FeedID:   goBlog.ID,
+		FolderID: uuid.NullUUID{UUID: tech.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+// TestRecorded replays each fixture in testdata against a freshly seeded
+// database.
+func TestRecorded(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".txt"), func(t *testing.T) {
+			exchanges, err := readExchanges(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := httptest.NewServer(greader.New(seed(t)))
+			defer srv.Close()
+			for _, ex := range exchanges {
+				ex.replay(t, srv, file)
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package greader
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/sanitize"
+)
+
+const (
+	defaultCount = 20
+	maxCount     = 1000
+)
+
+type itemRef struct {
+	ID            string `json:"id"`
+	TimestampUsec string `json:"timestampUsec"`
+}
+
+type link struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+type origin struct {
+	StreamID string `json:"streamId"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"htmlUrl"`
+}
+
+type content struct {
+	Direction string `json:"direction"`
+	Content   string `json:"content"`
+}
+
+type item struct {
+	ID            string   `json:"id"`
+	CrawlTimeMsec string   `json:"crawlTimeMsec"`
+	TimestampUsec string   `json:"timestampUsec"`
+	Published     int64    `json:"published"`
+	Updated       int64    `json:"updated"`
+	Title         string   `json:"title"`
+	Canonical     []link   `json:"canonical"`
+	Alternate     []link   `json:"alternate"`
+	Categories    []string `json:"categories"`
+	Origin        origin   `json:"origin"`
+	Summary       content  `json:"summary"`
+	Author        string   `json:"author"`
+}
+
+// itemID returns the long form of a post's item ID.
+func itemID(seq int64) string {
+	return fmt.Sprintf("tag:google.com,2005:reader/item/%016x", seq)
+}
+
+// parseItemID parses an item ID in its long form, with the number in hex, or
+// in its short form, in decimal.
+func parseItemID(id string) (int64, error) {
+	if hex, ok := strings.CutPrefix(id, "tag:google.com,2005:reader/item/"); ok {
+		n, err := strconv.ParseUint(hex, 16, 64)
+		return int64(n), err
+	}
+	return strconv.ParseInt(id, 10, 64)
+}
+
+func toItem(p database.GetGReaderItemsRow) item {
+	body := p.Content
+	if body == "" {
+		body = p.Description
+	}
+	body, err := sanitize.HTML(body, p.Url)
+	if err != nil {
+		body = ""
+	}
+	categories := []string{readingList}
+	if p.Read {
+		categories = append(categories, readState)
+	}
+	if p.Starred {
+		categories = append(categories, starredState)
+	}
+	if p.FolderName.Valid {
+		categories = append(categories, labelPrefix+p.FolderName.String)
+	}
+	return item{
+		ID:            itemID(p.Seq),
+		CrawlTimeMsec: strconv.FormatInt(p.CreatedAt.UnixMilli(), 10),
+		TimestampUsec: strconv.FormatInt(p.CreatedAt.UnixMicro(), 10),
+		Published:     p.PublishedAt.Unix(),
+		Updated:       p.UpdatedAt.Unix(),
+		Title:         p.Title,
+		Canonical:     []link{{Href: p.Url}},
+		Alternate:     []link{{Href: p.Url, Type: "text/html"}},
+		Categories:    categories,
+		Origin:        origin{feedPrefix + p.FeedUrl, p.FeedName, p.FeedSiteUrl},
+		Summary:       content{"ltr", body},
+	}
+}
+
+// errUnknownStream is returned by streamParams for streams that don't exist,
+// which are empty.
+var errUnknownStream = fmt.Errorf("unknown stream")
+
+// streamParams reads the stream, filters and paging of a stream request into
+// the parameters of a query.
+func streamParams(r *http.Request, user database.User, streamID string) (database.GetGReaderItemsParams, error) {
+	params := database.GetGReaderItemsParams{
+		UserID: user.ID,
+		Limit:  defaultCount,
+	}
+	if err := filterStream(&params, normalizeStream(streamID)); err != nil {
+		return params, err
+	}
+	for _, target := range r.Form["it"] {
+		if err := filterStream(&params, normalizeStream(target)); err != nil {
+			return params, err
+		}
+	}
+	for _, target := range r.Form["xt"] {
+		if normalizeStream(target) == readState {
+			params.UnreadOnly = true
+		}
+	}
+
+	if n, err := strconv.Atoi(r.Form.Get("n")); err == nil && n > 0 {
+		params.Limit = int32(min(n, maxCount))
+	}
+	params.OldestFirst = r.Form.Get("r") == "o"
+	if c, err := strconv.ParseInt(r.Form.Get("c"), 10, 64); err == nil {
+		params.Continuation = sql.NullInt64{Int64: c, Valid: true}
+	}
+	if ot, err := strconv.ParseInt(r.Form.Get("ot"), 10, 64); err == nil && ot > 0 {
+		params.NewerThan = sql.NullTime{Time: time.Unix(ot, 0), Valid: true}
+	}
+	if nt, err := strconv.ParseInt(r.Form.Get("nt"), 10, 64); err == nil && nt > 0 {
+		params.OlderThan = sql.NullTime{Time: time.Unix(nt, 0), Valid: true}
+	}
+	return params, nil
+}
+
+func filterStream(params *database.GetGReaderItemsParams, streamID string) error {
+	switch {
+	case streamID == "" || streamID == readingList:
+	case streamID == starredState:
+		params.StarredOnly = true
+	case streamID == readState:
+		params.ReadOnly = true
+	case strings.HasPrefix(streamID, labelPrefix):
+		params.Folder = sql.NullString{String: strings.TrimPrefix(streamID, labelPrefix), Valid: true}
+	case strings.HasPrefix(streamID, feedPrefix):
+		url, _ := feedURL(streamID)
+		params.Feed = sql.NullString{String: url, Valid: true}
+	default:
+		return errUnknownStream
+	}
+	return nil
+}
+
+// continuation returns the continuation for the page after posts, if there
+// may be one.
+func continuation(posts []database.GetGReaderItemsRow, limit int32) string {
+	if len(posts) < int(limit) || len(posts) == 0 {
+		return ""
+	}
+	return strconv.FormatInt(posts[len(posts)-1].Seq, 10)
+}
+
+func (s *Server) itemIDs(w http.ResponseWriter, r *http.Request, user database.User) error {
+	refs := []itemRef{}
+	params, err := streamParams(r, user, r.Form.Get("s"))
+	if err == errUnknownStream {
+		writeJSON(w, map[string]any{"itemRefs": refs})
+		return nil
+	}
+	posts, err := s.q.GetGReaderItems(r.Context(), params)
+	if err != nil {
+		return err
+	}
+	for _, p := range posts {
+		refs = append(refs, itemRef{strconv.FormatInt(p.Seq, 10), strconv.FormatInt(p.CreatedAt.UnixMicro(), 10)})
+	}
+	res := map[string]any{"itemRefs": refs}
+	if c := continuation(posts, params.Limit); c != "" {
+		res["continuation"] = c
+	}
+	writeJSON(w, res)
+	return nil
+}
+
+func (s *Server) streamContents(w http.ResponseWriter, r *http.Request, user database.User) error {
+	streamID := r.PathValue("stream")
+	if streamID == "" {
+		streamID = r.Form.Get("s")
+	}
+	if streamID == "" {
+		streamID = readingList
+	}
+	res := map[string]any{
+		"direction": "ltr",
+		"id":        streamID,
+		"updated":   time.Now().Unix(),
+		"items":     []item{},
+	}
+	params, err := streamParams(r, user, streamID)
+	if err == errUnknownStream {
+		writeJSON(w, res)
+		return nil
+	}
+	posts, err := s.q.GetGReaderItems(r.Context(), params)
+	if err != nil {
+		return err
+	}
+	items := []item{}
+	for _, p := range posts {
+		items = append(items, toItem(p))
+	}
+	res["items"] = items
+	if c := continuation(posts, params.Limit); c != "" {
+		res["continuation"] = c
+	}
+	writeJSON(w, res)
+	return nil
+}
+
+// itemContents returns the posts with the item IDs given in i.
+func (s *Server) itemContents(w http.ResponseWriter, r *http.Request, user database.User) error {
+	ids := []int64{}
+	for _, id := range r.Form["i"] {
+		seq, err := parseItemID(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid item ID '%s'", id), http.StatusBadRequest)
+			return nil
+		}
+		ids = append(ids, seq)
+	}
+	if len(ids) > maxCount {
+		ids = ids[:maxCount]
+	}
+	posts, err := s.q.GetGReaderItems(r.Context(), database.GetGReaderItemsParams{
+		UserID:  user.ID,
+		WithIds: ids,
+		Limit:   int32(len(ids)),
+	})
+	if err != nil {
+		return err
+	}
+	items := []item{}
+	for _, p := range posts {
+		items = append(items, toItem(p))
+	}
+	writeJSON(w, map[string]any{
+		"direction": "ltr",
+		"id":        readingList,
+		"updated":   time.Now().Unix(),
+		"items":     items,
+	})
+	return nil
+}
+
+// editTag marks the posts with the item IDs given in i read or unread, or
+// stars or unstars them.
+func (s *Server) editTag(w http.ResponseWriter, r *http.Request, user database.User) error {
+	add := map[string]bool{}
+	for _, tag := range r.Form["a"] {
+		add[normalizeStream(tag)] = true
+	}
+	remove := map[string]bool{}
+	for _, tag := range r.Form["r"] {
+		remove[normalizeStream(tag)] = true
+	}
+
+	ctx := r.Context()
+	for _, id := range r.Form["i"] {
+		seq, err := parseItemID(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid item ID '%s'", id), http.StatusBadRequest)
+			return nil
+		}
+		post, err := s.q.GetPostBySeq(ctx, seq)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case add[readState] || remove[keptUnread]:
+			err = s.q.MarkPostRead(ctx, database.MarkPostReadParams{CreatedAt: time.Now(), UserID: user.ID, PostID: post.ID})
+		case remove[readState] || add[keptUnread]:
+			err = s.q.MarkPostUnread(ctx, database.MarkPostUnreadParams{UserID: user.ID, PostID: post.ID})
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case add[starredState]:
+			err = s.q.StarPost(ctx, database.StarPostParams{CreatedAt: time.Now(), UserID: user.ID, PostID: post.ID})
+		case remove[starredState]:
+			err = s.q.UnstarPost(ctx, database.UnstarPostParams{UserID: user.ID, PostID: post.ID})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	writeText(w, "OK")
+	return nil
+}
+
+// markAllAsRead marks the posts of a feed, a label or all feeds read, up to
+// the time in ts, in microseconds.
+func (s *Server) markAllAsRead(w http.ResponseWriter, r *http.Request, user database.User) error {
+	ctx := r.Context()
+	params := database.MarkFeedsReadBeforeParams{
+		CreatedAt: time.Now(),
+		UserID:    user.ID,
+		Before:    time.Now(),
+	}
+	if ts, err := strconv.ParseInt(r.Form.Get("ts"), 10, 64); err == nil && ts > 0 {
+		params.Before = time.UnixMicro(ts)
+	}
+
+	streamID := normalizeStream(r.Form.Get("s"))
+	switch {
+	case streamID == readingList:
+	case strings.HasPrefix(streamID, labelPrefix):
+		folder, err := s.q.GetFolder(ctx, database.GetFolderParams{
+			UserID: user.ID,
+			Name:   strings.TrimPrefix(streamID, labelPrefix),
+		})
+		if err == sql.ErrNoRows {
+			http.Error(w, "label not found", http.StatusNotFound)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		params.FolderSeq = sql.NullInt64{Int64: folder.Seq, Valid: true}
+	case strings.HasPrefix(streamID, feedPrefix):
+		url, _ := feedURL(streamID)
+		feed, err := s.q.GetFeed(ctx, url)
+		if err == sql.ErrNoRows {
+			http.Error(w, "feed not found", http.StatusNotFound)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		params.FeedSeq = sql.NullInt64{Int64: feed.Seq, Valid: true}
+	default:
+		http.Error(w, fmt.Sprintf("can't mark stream '%s' as read", streamID), http.StatusBadRequest)
+		return nil
+	}
+	if err := s.q.MarkFeedsReadBefore(ctx, params); err != nil {
+		return err
+	}
+	writeText(w, "OK")
+	return nil
+}
+
+func (s *Server) unreadCount(w http.ResponseWriter, r *http.Request, user database.User) error {
+	counts, err := s.q.GetUnreadCounts(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	type count struct {
+		count  int64
+		newest time.Time
+	}
+	streams := map[string]*count{readingList: {}}
+	add := func(streamID string, n int64, newest time.Time) {
+		c, ok := streams[streamID]
+		if !ok {
+			c = &count{}
+			streams[streamID] = c
+		}
+		c.count += n
+		if newest.After(c.newest) {
+			c.newest = newest
+		}
+	}
+	for _, c := range counts {
+		add(feedPrefix+c.FeedUrl, c.Count, c.Newest)
+		if c.FolderName.Valid {
+			add(labelPrefix+c.FolderName.String, c.Count, c.Newest)
+		}
+		add(readingList, c.Count, c.Newest)
+	}
+
+	type unreadCount struct {
+		ID                      string `json:"id"`
+		Count                   int64  `json:"count"`
+		NewestItemTimestampUsec string `json:"newestItemTimestampUsec"`
+	}
+	res := []unreadCount{}
+	for streamID, c := range streams {
+		res = append(res, unreadCount{streamID, c.count, strconv.FormatInt(c.newest.UnixMicro(), 10)})
+	}
+	writeJSON(w, map[string]any{"max": maxCount, "unreadcounts": res})
+	return nil
+}
@@ -0,0 +1,246 @@
+package greader
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+type category struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type subscription struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Categories []category `json:"categories"`
+	URL        string     `json:"url"`
+	HTMLURL    string     `json:"htmlUrl"`
+	IconURL    string     `json:"iconUrl"`
+}
+
+func (s *Server) subscriptionList(w http.ResponseWriter, r *http.Request, user database.User) error {
+	feedFollows, err := s.q.GetFeedFollowsForUser(r.Context(), user.Name)
+	if err != nil {
+		return err
+	}
+	subscriptions := []subscription{}
+	for _, ff := range feedFollows {
+		categories := []category{}
+		if ff.FolderName.Valid {
+			categories = append(categories, category{labelPrefix + ff.FolderName.String, ff.FolderName.String})
+		}
+		subscriptions = append(subscriptions, subscription{
+			ID:         feedPrefix + ff.FeedUrl,
+			Title:      ff.FeedName,
+			Categories: categories,
+			URL:        ff.FeedUrl,
+			HTMLURL:    ff.FeedSiteUrl,
+		})
+	}
+	writeJSON(w, map[string]any{"subscriptions": subscriptions})
+	return nil
+}
+
+// subscriptionEdit follows (ac=subscribe) or unfollows (ac=unsubscribe) the
+// feeds in s, and moves them into the label in a or out of the one in r.
+// Feeds that aren't in the database yet are added, named after t.
+func (s *Server) subscriptionEdit(w http.ResponseWriter, r *http.Request, user database.User) error {
+	ctx := r.Context()
+	action := r.Form.Get("ac")
+	for _, streamID := range r.Form["s"] {
+		url, ok := feedURL(streamID)
+		if !ok {
+			http.Error(w, "invalid feed '"+streamID+"'", http.StatusBadRequest)
+			return nil
+		}
+
+		if action == "unsubscribe" {
+			err := s.q.DeleteFeedFollow(ctx, database.DeleteFeedFollowParams{UserID: user.ID, Url: url})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		var feed database.Feed
+		var err error
+		if action == "subscribe" {
+			feed, err = s.subscribe(ctx, user, url, r.Form.Get("t"))
+		} else {
+			feed, err = s.q.GetFeed(ctx, url)
+		}
+		if err == sql.ErrNoRows {
+			http.Error(w, "feed not found", http.StatusNotFound)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if label := normalizeStream(r.Form.Get("a")); strings.HasPrefix(label, labelPrefix) {
+			if err := s.moveToFolder(ctx, user, feed, strings.TrimPrefix(label, labelPrefix)); err != nil {
+				return err
+			}
+		} else if r.Form.Has("r") {
+			_, err := s.q.SetFeedFollowFolder(ctx, database.SetFeedFollowFolderParams{UserID: user.ID, FeedID: feed.ID})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	writeText(w, "OK")
+	return nil
+}
+
+func (s *Server) quickAdd(w http.ResponseWriter, r *http.Request, user database.User) error {
+	url := r.Form.Get("quickadd")
+	if u, ok := feedURL(url); ok {
+		url = u
+	}
+	if url == "" {
+		http.Error(w, "missing quickadd", http.StatusBadRequest)
+		return nil
+	}
+	feed, err := s.subscribe(r.Context(), user, url, "")
+	if err != nil {
+		return err
+	}
+	writeJSON(w, map[string]any{
+		"numResults": 1,
+		"query":      url,
+		"streamId":   feedPrefix + feed.Url,
+		"streamName": feed.Name,
+	})
+	return nil
+}
+
+// subscribe follows the feed with the given URL, adding it to the database
+// first if it isn't there, named after title or its URL.
+func (s *Server) subscribe(ctx context.Context, user database.User, url, title string) (database.Feed, error) {
+	feed, err := s.q.GetFeed(ctx, url)
+	if err == sql.ErrNoRows {
+		if title == "" {
+			title = url
+		}
+		feed, err = s.q.AddFeed(ctx, database.AddFeedParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      title,
+			Url:       url,
+			UserID:    user.ID,
+		})
+	}
+	if err != nil {
+		return feed, err
+	}
+
+	_, err = s.q.GetFeedFollow(ctx, database.GetFeedFollowParams{UserID: user.ID, FeedID: feed.ID})
+	if err != sql.ErrNoRows {
+		return feed, err
+	}
+	_, err = s.q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	return feed, err
+}
+
+// moveToFolder moves a followed feed into the user's folder with the given
+// name, creating it if needed.
+func (s *Server) moveToFolder(ctx context.Context, user database.User, feed database.Feed, name string) error {
+	folder, err := s.q.GetFolder(ctx, database.GetFolderParams{UserID: user.ID, Name: name})
+	if err == sql.ErrNoRows {
+		folder, err = s.q.CreateFolder(ctx, database.CreateFolderParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      name,
+			UserID:    user.ID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.q.SetFeedFollowFolder(ctx, database.SetFeedFollowFolderParams{
+		UserID:   user.ID,
+		FeedID:   feed.ID,
+		FolderID: uuid.NullUUID{UUID: folder.ID, Valid: true},
+	})
+	return err
+}
+
+func (s *Server) tagList(w http.ResponseWriter, r *http.Request, user database.User) error {
+	folders, err := s.q.GetFoldersForUser(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	type tag struct {
+		ID   string `json:"id"`
+		Type string `json:"type,omitempty"`
+	}
+	tags := []tag{{ID: starredState}}
+	for _, folder := range folders {
+		tags = append(tags, tag{labelPrefix + folder.Name, "folder"})
+	}
+	writeJSON(w, map[string]any{"tags": tags})
+	return nil
+}
+
+func (s *Server) renameTag(w http.ResponseWriter, r *http.Request, user database.User) error {
+	folder, ok, err := s.labelFolder(w, r, user)
+	if !ok {
+		return err
+	}
+	dest, isLabel := strings.CutPrefix(normalizeStream(r.Form.Get("dest")), labelPrefix)
+	if !isLabel || dest == "" {
+		http.Error(w, "invalid dest", http.StatusBadRequest)
+		return nil
+	}
+	if err := s.q.RenameFolder(r.Context(), database.RenameFolderParams{ID: folder.ID, Name: dest}); err != nil {
+		return err
+	}
+	writeText(w, "OK")
+	return nil
+}
+
+// disableTag deletes a folder. Its feeds stay followed.
+func (s *Server) disableTag(w http.ResponseWriter, r *http.Request, user database.User) error {
+	folder, ok, err := s.labelFolder(w, r, user)
+	if !ok {
+		return err
+	}
+	if err := s.q.DeleteFolder(r.Context(), folder.ID); err != nil {
+		return err
+	}
+	writeText(w, "OK")
+	return nil
+}
+
+// labelFolder looks up the folder of the label in s, reporting missing ones.
+func (s *Server) labelFolder(w http.ResponseWriter, r *http.Request, user database.User) (database.Folder, bool, error) {
+	name, ok := strings.CutPrefix(normalizeStream(r.Form.Get("s")), labelPrefix)
+	if !ok {
+		http.Error(w, "invalid label", http.StatusBadRequest)
+		return database.Folder{}, false, nil
+	}
+	folder, err := s.q.GetFolder(r.Context(), database.GetFolderParams{UserID: user.ID, Name: name})
+	if err == sql.ErrNoRows {
+		http.Error(w, "label not found", http.StatusNotFound)
+		return folder, false, nil
+	}
+	if err != nil {
+		return folder, false, err
+	}
+	return folder, true, nil
+}
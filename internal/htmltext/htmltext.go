@@ -0,0 +1,380 @@
+package htmltext
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Render turns an HTML fragment, such as a post description, into plain text
+// wrapped to width columns. Links and images are numbered in the text and
+// listed as footnotes at the end, resolved against baseURL.
+func Render(s string, baseURL string, width int) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		// The parser accepts any input, but fall back to the raw text anyway.
+		return strings.Join(Wrap(s, width), "\n")
+	}
+
+	r := &renderer{width: max(width, 20), links: map[string]int{}}
+	r.base, _ = url.Parse(baseURL)
+	r.walk(doc)
+	r.flush()
+
+	text := strings.Join(r.lines, "\n")
+	if len(r.footnotes) > 0 {
+		text += "\n\n" + strings.Join(r.footnotes, "\n")
+	}
+	return text
+}
+
+// Summary returns the text of an HTML fragment on one line, cut after about
+// length characters at a word boundary.
+func Summary(s string, length int) string {
+	doc, err := html.Parse(strings.NewReader(s))
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && skipped[n.DataAtom] {
+			return
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(Clean(n.Data))
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+
+	summary := ""
+	for _, word := range strings.Fields(sb.String()) {
+		if summary != "" && utf8.RuneCountInString(summary)+1+utf8.RuneCountInString(word) > length {
+			return summary + " …"
+		}
+		if summary != "" {
+			summary += " "
+		}
+		summary += word
+	}
+	return summary
+}
+
+// Clean removes the control characters from text, except newlines and tabs,
+// so that feeds can't send escape sequences to the terminal it's shown in.
+func Clean(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+type renderer struct {
+	width int
+	base  *url.URL
+	lines []string
+
+	// inline collects the text of the current paragraph.
+	inline strings.Builder
+	// indent is the prefix of every line of the current block, made of the
+	// markers of the blockquotes and lists it's nested in.
+	indent string
+	// bullet is the marker of the list item the current paragraph starts,
+	// which hangs in the margin of indent.
+	bullet string
+	// lists holds the number of the next item of each list the current block
+	// is nested in, or 0 for unordered lists.
+	lists []int
+	// afterItem is true if the last paragraph started a list item. The
+	// paragraphs starting consecutive items aren't separated by blank lines.
+	afterItem bool
+
+	links     map[string]int
+	footnotes []string
+}
+
+var skipped = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+}
+
+var blocks = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Div:        true,
+	atom.Section:    true,
+	atom.Article:    true,
+	atom.Header:     true,
+	atom.Footer:     true,
+	atom.Main:       true,
+	atom.Aside:      true,
+	atom.Nav:        true,
+	atom.Figure:     true,
+	atom.Figcaption: true,
+	atom.Table:      true,
+	atom.Tr:         true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Dd:         true,
+	atom.Details:    true,
+	atom.Summary:    true,
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.inline.WriteString(Clean(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	switch {
+	case skipped[n.DataAtom]:
+	case blocks[n.DataAtom]:
+		r.flush()
+		r.children(n)
+		r.flush()
+	case n.DataAtom == atom.Br:
+		r.inline.WriteString("\n")
+	case n.DataAtom == atom.Hr:
+		r.flush()
+		r.paragraphBreak()
+		r.add(r.indent + strings.Repeat("─", min(r.width-utf8.RuneCountInString(r.indent), 40)))
+	case headingLevel(n.DataAtom) > 0:
+		r.flush()
+		r.inline.WriteString(strings.Repeat("#", headingLevel(n.DataAtom)) + " ")
+		r.children(n)
+		r.flush()
+	case n.DataAtom == atom.Ul || n.DataAtom == atom.Ol:
+		r.flush()
+		start := 0
+		if n.DataAtom == atom.Ol {
+			start = 1
+		}
+		r.lists = append(r.lists, start)
+		r.children(n)
+		r.flush()
+		r.lists = r.lists[:len(r.lists)-1]
+	case n.DataAtom == atom.Li:
+		r.flush()
+		bullet := "• "
+		if len(r.lists) > 0 && r.lists[len(r.lists)-1] > 0 {
+			bullet = fmt.Sprintf("%d. ", r.lists[len(r.lists)-1])
+			r.lists[len(r.lists)-1]++
+		}
+		outer := r.indent
+		r.bullet = bullet
+		r.indent += strings.Repeat(" ", utf8.RuneCountInString(bullet))
+		r.children(n)
+		r.flush()
+		r.indent = outer
+	case n.DataAtom == atom.Blockquote:
+		r.flush()
+		outer := r.indent
+		r.indent += "│ "
+		r.children(n)
+		r.flush()
+		r.indent = outer
+	case n.DataAtom == atom.Pre:
+		r.flush()
+		r.paragraphBreak()
+		text := strings.TrimRight(Clean(textContent(n)), "\n ")
+		for _, line := range strings.Split(text, "\n") {
+			r.add(r.indent + "    " + strings.ReplaceAll(line, "\t", "    "))
+		}
+	case n.DataAtom == atom.Code:
+		r.inline.WriteString("`")
+		r.children(n)
+		r.inline.WriteString("`")
+	case n.DataAtom == atom.A:
+		r.children(n)
+		if href := r.resolve(attr(n, "href")); href != "" && href != strings.TrimSpace(textContent(n)) {
+			fmt.Fprintf(&r.inline, "[%d]", r.footnote(href))
+		}
+	case n.DataAtom == atom.Img:
+		alt := strings.TrimSpace(Clean(attr(n, "alt")))
+		if alt == "" {
+			alt = "image"
+		} else {
+			alt = "image: " + alt
+		}
+		r.inline.WriteString("[" + alt + "]")
+		if src := r.resolve(attr(n, "src")); src != "" {
+			fmt.Fprintf(&r.inline, "[%d]", r.footnote(src))
+		}
+	case n.DataAtom == atom.Td || n.DataAtom == atom.Th:
+		r.children(n)
+		r.inline.WriteString("  ")
+	default:
+		r.children(n)
+	}
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+}
+
+// flush wraps the current paragraph and adds it to the output.
+func (r *renderer) flush() {
+	text := r.inline.String()
+	r.inline.Reset()
+
+	segments := []string{}
+	for _, segment := range strings.Split(text, "\n") {
+		segment = strings.Join(strings.Fields(segment), " ")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	if r.bullet == "" || !r.afterItem {
+		r.paragraphBreak()
+	}
+	first := r.indent
+	rest := r.indent
+	r.afterItem = r.bullet != ""
+	if r.bullet != "" {
+		first = r.indent[:len(r.indent)-utf8.RuneCountInString(r.bullet)] + r.bullet
+		r.bullet = ""
+	}
+	width := r.width - utf8.RuneCountInString(rest)
+	for _, segment := range segments {
+		for _, line := range Wrap(segment, width) {
+			r.add(first + line)
+			first = rest
+		}
+	}
+}
+
+// paragraphBreak separates the next block from the previous one with a blank
+// line.
+func (r *renderer) paragraphBreak() {
+	if len(r.lines) > 0 && r.lines[len(r.lines)-1] != "" {
+		r.lines = append(r.lines, "")
+	}
+}
+
+func (r *renderer) add(line string) {
+	r.lines = append(r.lines, strings.TrimRight(line, " "))
+}
+
+func (r *renderer) footnote(link string) int {
+	if n, ok := r.links[link]; ok {
+		return n
+	}
+	r.footnotes = append(r.footnotes, fmt.Sprintf("[%d] %s", len(r.footnotes)+1, link))
+	r.links[link] = len(r.footnotes)
+	return len(r.footnotes)
+}
+
+// resolve makes link absolute, and drops links that don't lead anywhere
+// useful outside of the page, such as anchors and scripts.
+func (r *renderer) resolve(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") {
+		return ""
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	if r.base != nil {
+		u = r.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto" {
+		return ""
+	}
+	return u.String()
+}
+
+func headingLevel(a atom.Atom) int {
+	switch a {
+	case atom.H1:
+		return 1
+	case atom.H2:
+		return 2
+	case atom.H3:
+		return 3
+	case atom.H4:
+		return 4
+	case atom.H5:
+		return 5
+	case atom.H6:
+		return 6
+	}
+	return 0
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+// Wrap splits text into lines of at most width columns, breaking at spaces
+// where possible. Newlines in text are kept.
+func Wrap(text string, width int) []string {
+	width = max(width, 1)
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
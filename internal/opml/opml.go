@@ -0,0 +1,128 @@
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+	OwnerName   string `xml:"ownerName,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a feed subscription found in an OPML document, along with the path
+// of the outlines it was nested in.
+type Feed struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	Folders []string
+}
+
+// Parse reads an OPML 1.0 or 2.0 document.
+func Parse(r io.Reader) (*OPML, error) {
+	doc := OPML{}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("couldn't parse OPML: %w", err)
+	}
+	return &doc, nil
+}
+
+// Feeds returns every feed subscription in the document, in document order.
+func (o *OPML) Feeds() []Feed {
+	feeds := []Feed{}
+	var walk func(outlines []Outline, folders []string)
+	walk = func(outlines []Outline, folders []string) {
+		for _, outline := range outlines {
+			title := outline.Title
+			if title == "" {
+				title = outline.Text
+			}
+			if outline.XMLURL != "" {
+				feeds = append(feeds, Feed{
+					Title:   strings.TrimSpace(title),
+					XMLURL:  strings.TrimSpace(outline.XMLURL),
+					HTMLURL: strings.TrimSpace(outline.HTMLURL),
+					Folders: folders,
+				})
+			}
+			if len(outline.Outlines) > 0 {
+				walk(outline.Outlines, append(folders[:len(folders):len(folders)], strings.TrimSpace(title)))
+			}
+		}
+	}
+	walk(o.Body.Outlines, nil)
+	return feeds
+}
+
+// New creates an empty OPML 2.0 document.
+func New(title string) *OPML {
+	return &OPML{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: time.Now().Format(time.RFC1123Z),
+		},
+	}
+}
+
+// AddFeed adds a feed subscription to the document, nesting it inside an
+// outline for each of its folders.
+func (o *OPML) AddFeed(feed Feed) {
+	outlines := &o.Body.Outlines
+	for _, folder := range feed.Folders {
+		i := 0
+		for i < len(*outlines) && ((*outlines)[i].XMLURL != "" || (*outlines)[i].Text != folder) {
+			i++
+		}
+		if i == len(*outlines) {
+			*outlines = append(*outlines, Outline{Text: folder, Title: folder})
+		}
+		outlines = &(*outlines)[i].Outlines
+	}
+	*outlines = append(*outlines, Outline{
+		Text:    feed.Title,
+		Title:   feed.Title,
+		Type:    "rss",
+		XMLURL:  feed.XMLURL,
+		HTMLURL: feed.HTMLURL,
+	})
+}
+
+// Write writes the document as indented XML.
+func (o *OPML) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(o); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+)
+
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+	Table Format = "table"
+)
+
+var Formats = []Format{Text, JSON, CSV, TSV, Table}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format '%s', expected one of: text, json, csv, tsv, table", s)
+}
+
+// List holds records that all have the same fields, named by Columns.
+type List struct {
+	Columns []string
+	Rows    [][]any
+}
+
+func NewList(columns ...string) *List {
+	return &List{Columns: columns}
+}
+
+// Add appends a record, with one value per column. A nil value is rendered as
+// null in JSON and as an empty string in the other formats.
+func (l *List) Add(values ...any) {
+	l.Rows = append(l.Rows, values)
+}
+
+// Render writes the list to w in one of the structured formats. Text output
+// is specific to every command, so it isn't handled here.
+func Render(w io.Writer, format Format, l *List) error {
+	switch format {
+	case JSON:
+		return renderJSON(w, l)
+	case CSV:
+		return renderCSV(w, l)
+	case TSV:
+		return renderTSV(w, l)
+	case Table:
+		return renderTable(w, l)
+	}
+	return fmt.Errorf("output format '%s' can't be rendered as a list", format)
+}
+
+func renderJSON(w io.Writer, l *List) error {
+	// The objects are built by hand to keep the fields in column order.
+	buf := bytes.Buffer{}
+	buf.WriteByte('[')
+	for i, row := range l.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(l.Columns[j])
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(val)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	out := bytes.Buffer{}
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(w)
+	return err
+}
+
+func renderCSV(w io.Writer, l *List) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(l.Columns); err != nil {
+		return err
+	}
+	for _, row := range l.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatValue(value, time.RFC3339)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderTSV(w io.Writer, l *List) error {
+	// TSV has no quoting, so tabs and newlines inside values become spaces.
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	if _, err := fmt.Fprintln(w, strings.Join(l.Columns, "\t")); err != nil {
+		return err
+	}
+	for _, row := range l.Rows {
+		fields := make([]string, len(row))
+		for i, value := range row {
+			fields[i] = clean.Replace(formatValue(value, time.RFC3339))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderTable(w io.Writer, l *List) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(l.Columns))
+	for i, column := range l.Columns {
+		headers[i] = strings.ToUpper(strings.ReplaceAll(column, "_", " "))
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range l.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			// Tabs and newlines would break the alignment of the table.
+			// Control characters, like escape sequences a feed put in a
+			// title, are removed before the table reaches the terminal.
+			cells[i] = strings.Join(strings.Fields(htmltext.Clean(formatValue(value, "2006-01-02 15:04"))), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatValue(value any, timeLayout string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(timeLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(timeLayout)
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return fmt.Sprint(value)
+}
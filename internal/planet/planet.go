@@ -0,0 +1,310 @@
+package planet
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/publish"
+	"github.com/google/uuid"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+const (
+	// indexSize is the number of latest posts on the index page.
+	indexSize = 30
+	dayFormat = "Monday, January 2, 2006"
+)
+
+// Pages are the templates of the site's pages. Each is executed together with
+// layout.html, which wraps its "content" template, and posts.html, which
+// defines the "days" template listing posts by day.
+var Pages = []string{"index.html", "feed.html", "day.html"}
+
+// Site is a Planet-style page aggregating the posts of a set of feeds.
+type Site struct {
+	Title string
+	// URL is the address the site is hosted at, used for absolute links in
+	// its Atom feed. It can be empty.
+	URL       string
+	Feeds     []*Feed
+	Days      []*Day
+	Generated time.Time
+}
+
+type Feed struct {
+	ID      uuid.UUID
+	Name    string
+	URL     string
+	SiteURL string
+	// Path is where the feed's page is, relative to the site's root.
+	Path  string
+	Posts []*Post
+}
+
+// Day is the posts published on one day, in local time, newest first.
+type Day struct {
+	Date  time.Time
+	Path  string
+	Posts []*Post
+}
+
+type Post struct {
+	publish.Entry
+	Feed *Feed
+}
+
+// HTML is the post's content, which was sanitized when it was published.
+func (p *Post) HTML() template.HTML {
+	return template.HTML(p.Content)
+}
+
+// New returns a site with the given feeds and the posts in entries, which
+// are newest first. Posts from feeds that aren't in feeds are left out.
+func New(title, url string, feeds []*Feed, entries []publish.Entry) *Site {
+	site := &Site{Title: title, URL: strings.TrimSuffix(url, "/"), Feeds: feeds, Generated: time.Now()}
+
+	byURL := map[string]*Feed{}
+	used := map[string]bool{}
+	for _, feed := range feeds {
+		slug := slugify(feed.Name)
+		if used[slug] {
+			slug += "-" + feed.ID.String()[:8]
+		}
+		used[slug] = true
+		feed.Path = "feeds/" + slug + ".html"
+		byURL[feed.URL] = feed
+	}
+
+	var posts []*Post
+	for _, e := range entries {
+		feed, ok := byURL[e.SourceURL]
+		if !ok {
+			continue
+		}
+		post := &Post{Entry: e, Feed: feed}
+		feed.Posts = append(feed.Posts, post)
+		posts = append(posts, post)
+	}
+	site.Days = groupByDay(posts)
+	return site
+}
+
+// page is the data every page is rendered with. Root is the relative path
+// from the page to the site's root, so links work without a web server too.
+type page struct {
+	Site  *Site
+	Title string
+	Root  string
+	Days  []*Day
+	Feed  *Feed
+}
+
+// Build renders the site into dir: index.html, a page per feed and per day,
+// atom.xml with all the posts, and the files in the templates' static
+// directory. If templateDir isn't empty, the templates in it replace the
+// default ones with the same name, and its static directory replaces the
+// default one.
+func (site *Site) Build(dir, templateDir string) error {
+	files := fs.FS(mustSub(defaultTemplates, "templates"))
+	if templateDir != "" {
+		files = overlay{os.DirFS(templateDir), files}
+	}
+	pages, err := parsePages(files)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range []string{"feeds", "days"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := render(pages, "index.html", dir, "index.html", page{
+		Site:  site,
+		Title: site.Title,
+		Days:  latest(site.Days, indexSize),
+	}); err != nil {
+		return err
+	}
+	for _, feed := range site.Feeds {
+		if err := render(pages, "feed.html", dir, feed.Path, page{
+			Site:  site,
+			Title: feed.Name,
+			Root:  "../",
+			Days:  groupByDay(feed.Posts),
+			Feed:  feed,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, day := range site.Days {
+		if err := render(pages, "day.html", dir, day.Path, page{
+			Site:  site,
+			Title: day.Date.Format(dayFormat),
+			Root:  "../",
+			Days:  []*Day{day},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := site.writeAtom(filepath.Join(dir, "atom.xml")); err != nil {
+		return err
+	}
+	return copyStatic(files, dir)
+}
+
+func parsePages(files fs.FS) (map[string]*template.Template, error) {
+	funcs := template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.Local().Format("Jan 2, 2006 15:04")
+		},
+		"day": func(t time.Time) string {
+			return t.Format(dayFormat)
+		},
+	}
+	layout, err := template.New("layout.html").Funcs(funcs).ParseFS(files, "layout.html", "posts.html")
+	if err != nil {
+		return nil, err
+	}
+	pages := map[string]*template.Template{}
+	for _, name := range Pages {
+		t, err := template.Must(layout.Clone()).ParseFS(files, name)
+		if err != nil {
+			return nil, err
+		}
+		pages[name] = t
+	}
+	return pages, nil
+}
+
+func render(pages map[string]*template.Template, name, dir, file string, p page) error {
+	var buf bytes.Buffer
+	if err := pages[name].Execute(&buf, p); err != nil {
+		return fmt.Errorf("couldn't render %s: %w", file, err)
+	}
+	return os.WriteFile(filepath.Join(dir, filepath.FromSlash(file)), buf.Bytes(), 0644)
+}
+
+func (site *Site) writeAtom(file string) error {
+	feed := publish.Feed{
+		ID:      "urn:gator:planet:" + slugify(site.Title),
+		Title:   site.Title,
+		Updated: site.Generated,
+	}
+	if site.URL != "" {
+		feed.ID = site.URL + "/"
+		feed.Link = site.URL + "/atom.xml"
+	}
+	for _, day := range site.Days {
+		for _, post := range day.Posts {
+			feed.Entries = append(feed.Entries, post.Entry)
+		}
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := publish.Write(f, feed, publish.Atom); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// copyStatic copies the files in the templates' static directory to the
+// site's root.
+func copyStatic(files fs.FS, dir string) error {
+	return fs.WalkDir(files, "static", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "static")))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, data, 0644)
+	})
+}
+
+// latest returns the days of the n latest posts.
+func latest(days []*Day, n int) []*Day {
+	var result []*Day
+	for _, day := range days {
+		if n <= 0 {
+			break
+		}
+		if len(day.Posts) > n {
+			day = &Day{Date: day.Date, Path: day.Path, Posts: day.Posts[:n]}
+		}
+		result = append(result, day)
+		n -= len(day.Posts)
+	}
+	return result
+}
+
+// groupByDay returns the days posts were published on, newest first.
+func groupByDay(posts []*Post) []*Day {
+	var days []*Day
+	for _, post := range posts {
+		date := post.Published.Local().Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Path != "days/"+date+".html" {
+			t := post.Published.Local()
+			days = append(days, &Day{
+				Date: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local),
+				Path: "days/" + date + ".html",
+			})
+		}
+		days[len(days)-1].Posts = append(days[len(days)-1].Posts, post)
+	}
+	return days
+}
+
+// slugify returns the lowercase words of s joined by dashes, for file names.
+func slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	})
+	if len(words) == 0 {
+		return "feed"
+	}
+	return strings.Join(words, "-")
+}
+
+// overlay is a file system whose files replace the ones with the same name in
+// another.
+type overlay struct {
+	top, bottom fs.FS
+}
+
+func (o overlay) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.bottom.Open(name)
+	}
+	return f, err
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,146 @@
+package publish
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// Handler serves users' timelines as feeds at /users/{name}/feed.xml, so that
+// other readers can subscribe to them. The format, folder and limit query
+// parameters pick what's in the feed. If token isn't empty, requests need
+// either the user's feed token in the token query parameter, since feed
+// readers rarely let users set headers, or token as a bearer token. Feed
+// tokens only give access to the user's feeds, so unlike token they're safe
+// to put in URLs.
+type Handler struct {
+	q     *database.Queries
+	token string
+	mux   *http.ServeMux
+}
+
+func NewHandler(q *database.Queries, token string) *Handler {
+	h := &Handler{q: q, token: token, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /users/{name}/feed.xml", h.feed)
+	h.mux.HandleFunc("/users/", http.NotFound)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether the request may read user's feeds.
+func (h *Handler) authorized(r *http.Request, user database.User) (bool, error) {
+	if h.token == "" {
+		return true, nil
+	}
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return subtle.ConstantTimeCompare([]byte(bearer), []byte(h.token)) == 1, nil
+	}
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return false, nil
+	}
+	saved, err := h.q.GetFeedToken(r.Context(), user.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(saved.Token)) == 1, nil
+}
+
+func (h *Handler) feed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := Atom
+	if query.Has("format") {
+		var err error
+		if format, err = ParseFormat(query.Get("format")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultLimit
+	if query.Has("limit") {
+		n, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || n < 1 || n > maxLimit {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	user, err := h.q.GetUser(r.Context(), r.PathValue("name"))
+	if err == sql.ErrNoRows && h.token != "" {
+		// Don't tell who has an account to requests without a token.
+		http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "user '"+r.PathValue("name")+"' not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+	ok, err := h.authorized(r, user)
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	folder := query.Get("folder")
+	if folder != "" {
+		if _, err := h.q.GetFolder(r.Context(), database.GetFolderParams{UserID: user.ID, Name: folder}); err == sql.ErrNoRows {
+			http.Error(w, "folder '"+folder+"' not found", http.StatusNotFound)
+			return
+		}
+	}
+	feed, err := Timeline(r.Context(), h.q, user, folder, limit)
+	if err != nil {
+		internalError(w, r, err)
+		return
+	}
+	feed.Link = selfLink(r)
+
+	w.Header().Set("Content-Type", format.ContentType())
+	if err := Write(w, feed, format); err != nil {
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// selfLink returns the URL the feed was requested at, without the token.
+func selfLink(r *http.Request) string {
+	u := *r.URL
+	u.Host = r.Host
+	u.Scheme = "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		u.Scheme = "https"
+	}
+	query := u.Query()
+	query.Del("token")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
@@ -0,0 +1,303 @@
+package publish
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/sanitize"
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	Atom Format = "atom"
+	RSS  Format = "rss"
+)
+
+var Formats = []Format{Atom, RSS}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown feed format '%s', expected one of atom, rss", s)
+}
+
+// ContentType is the MIME type of feeds in the format.
+func (f Format) ContentType() string {
+	if f == RSS {
+		return "application/rss+xml; charset=utf-8"
+	}
+	return "application/atom+xml; charset=utf-8"
+}
+
+// Feed is a feed of posts to publish.
+type Feed struct {
+	// ID identifies the feed permanently, as a URI.
+	ID    string
+	Title string
+	// Link is the address the feed is published at, if known.
+	Link    string
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	ID        uuid.UUID
+	Title     string
+	URL       string
+	Published time.Time
+	Updated   time.Time
+	// Content is sanitized HTML.
+	Content string
+	// Source is the feed the post comes from.
+	Source    string
+	SourceURL string
+	SiteURL   string
+}
+
+// Timeline returns the latest posts from the feeds user follows, or from the
+// ones in folder if it isn't empty, newest first.
+func Timeline(ctx context.Context, q *database.Queries, user database.User, folder string, limit int) (Feed, error) {
+	feed := Feed{
+		ID:    "urn:uuid:" + user.ID.String(),
+		Title: fmt.Sprintf("Posts followed by %s in Gator", user.Name),
+	}
+	if folder != "" {
+		f, err := q.GetFolder(ctx, database.GetFolderParams{UserID: user.ID, Name: folder})
+		if err == sql.ErrNoRows {
+			return feed, fmt.Errorf("folder '%s' not found", folder)
+		}
+		if err != nil {
+			return feed, err
+		}
+		feed.ID = "urn:uuid:" + f.ID.String()
+		feed.Title = fmt.Sprintf("Posts in %s's %s folder in Gator", user.Name, f.Name)
+	}
+
+	feedFollows, err := q.GetFeedFollowsForUser(ctx, user.Name)
+	if err != nil {
+		return feed, err
+	}
+	sources := map[uuid.UUID]database.GetFeedFollowsForUserRow{}
+	for _, ff := range feedFollows {
+		sources[ff.FeedID] = ff
+	}
+
+	posts, err := q.GetPostsForUser(ctx, database.GetPostsForUserParams{
+		UserID: user.ID,
+		Folder: sql.NullString{String: folder, Valid: folder != ""},
+		Sort:   "published",
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return feed, err
+	}
+	for _, p := range posts {
+		entry, err := NewEntry(p, sources[p.FeedID])
+		if err != nil {
+			return feed, err
+		}
+		feed.Entries = append(feed.Entries, entry)
+		feed.Updated = maxTime(feed.Updated, p.UpdatedAt)
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+	return feed, nil
+}
+
+// NewEntry returns the entry of a post from the followed feed source, with its
+// full text if it was extracted.
+func NewEntry(p database.GetPostsForUserRow, source database.GetFeedFollowsForUserRow) (Entry, error) {
+	content := p.Content
+	if content == "" {
+		content = p.Description
+	}
+	content, err := sanitize.HTML(content, p.Url)
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{
+		ID:        p.ID,
+		Title:     p.Title,
+		URL:       p.Url,
+		Published: p.PublishedAt,
+		Updated:   p.UpdatedAt,
+		Content:   content,
+		Source:    source.FeedName,
+		SourceURL: source.FeedUrl,
+		SiteURL:   source.FeedSiteUrl,
+	}, nil
+}
+
+// Write writes the feed as indented XML in the given format.
+func Write(w io.Writer, feed Feed, format Format) error {
+	var doc any
+	if format == RSS {
+		doc = toRSS(feed)
+	} else {
+		doc = toAtom(feed)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Link      atomLink    `xml:"link"`
+	Author    atomAuthor  `xml:"author"`
+	Source    *atomSource `xml:"source,omitempty"`
+	Content   atomText    `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomSource struct {
+	ID    string     `xml:"id"`
+	Title string     `xml:"title"`
+	Links []atomLink `xml:"link"`
+}
+
+func toAtom(feed Feed) atomFeed {
+	doc := atomFeed{
+		ID:        feed.ID,
+		Title:     feed.Title,
+		Updated:   feed.Updated.UTC().Format(time.RFC3339),
+		Generator: "Gator",
+	}
+	if feed.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: feed.Link, Rel: "self", Type: "application/atom+xml"})
+	}
+	for _, e := range feed.Entries {
+		entry := atomEntry{
+			ID:        "urn:uuid:" + e.ID.String(),
+			Title:     e.Title,
+			Published: e.Published.UTC().Format(time.RFC3339),
+			Updated:   e.Updated.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: e.URL, Rel: "alternate"},
+			Author:    atomAuthor{Name: e.Source},
+			Content:   atomText{Type: "html", Text: e.Content},
+		}
+		if e.SourceURL != "" {
+			source := &atomSource{ID: e.SourceURL, Title: e.Source}
+			source.Links = append(source.Links, atomLink{Href: e.SourceURL, Rel: "self"})
+			if e.SiteURL != "" {
+				source.Links = append(source.Links, atomLink{Href: e.SiteURL, Rel: "alternate"})
+			}
+			entry.Source = source
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	Description string     `xml:"description"`
+	PubDate     string     `xml:"pubDate"`
+	GUID        rssGUID    `xml:"guid"`
+	Source      *rssSource `xml:"source,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Text        string `xml:",chardata"`
+}
+
+type rssSource struct {
+	URL  string `xml:"url,attr"`
+	Text string `xml:",chardata"`
+}
+
+func toRSS(feed Feed) rssDoc {
+	doc := rssDoc{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Title,
+			LastBuildDate: feed.Updated.Format(time.RFC1123Z),
+			Generator:     "Gator",
+		},
+	}
+	for _, e := range feed.Entries {
+		item := rssItem{
+			Title:       e.Title,
+			Link:        e.URL,
+			Description: e.Content,
+			PubDate:     e.Published.Format(time.RFC1123Z),
+			GUID:        rssGUID{Text: "urn:uuid:" + e.ID.String()},
+		}
+		if e.SourceURL != "" {
+			item.Source = &rssSource{URL: e.SourceURL, Text: e.Source}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return doc
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
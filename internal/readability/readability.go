@@ -0,0 +1,310 @@
+package readability
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrNoArticle is returned by Extract for pages that don't seem to contain an
+// article, such as index pages made of links.
+var ErrNoArticle = errors.New("no article found")
+
+var (
+	unlikelyPattern = regexp.MustCompile(`(?i)banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
+	maybePattern    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positivePattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativePattern = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// removed are the elements that are never part of an article.
+var removed = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Iframe:   true,
+	atom.Form:     true,
+	atom.Nav:      true,
+	atom.Aside:    true,
+	atom.Footer:   true,
+	atom.Svg:      true,
+	atom.Button:   true,
+	atom.Input:    true,
+	atom.Select:   true,
+	atom.Textarea: true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Link:     true,
+	atom.Meta:     true,
+}
+
+// keptAttributes are the attributes left on the elements of the article.
+var keptAttributes = map[string]bool{
+	"href":  true,
+	"src":   true,
+	"alt":   true,
+	"title": true,
+}
+
+var blockElements = map[atom.Atom]bool{
+	atom.Address:    true,
+	atom.Article:    true,
+	atom.Blockquote: true,
+	atom.Div:        true,
+	atom.Dl:         true,
+	atom.Figure:     true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Ol:         true,
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Section:    true,
+	atom.Table:      true,
+	atom.Ul:         true,
+}
+
+// Extract finds the main article of an HTML page and returns it as HTML,
+// without the navigation, sidebars, comments and scripts around it.
+//
+// It follows the approach of Arc90's Readability: paragraphs are scored by
+// their length and number of commas, their scores are added to their parent
+// and grandparent elements, and the best scoring element, adjusted by its
+// class and link density, is taken as the article, together with any
+// siblings that look like part of it.
+func Extract(r io.Reader) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	body := find(doc, atom.Body)
+	if body == nil {
+		return "", ErrNoArticle
+	}
+	clean(body)
+
+	scores := map[*html.Node]float64{}
+	candidates := []*html.Node{}
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode || n.DataAtom == atom.Html {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	walk(body, func(n *html.Node) {
+		if !isParagraph(n) {
+			return
+		}
+		text := innerText(n)
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text)/100), 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+	})
+
+	var top *html.Node
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(n)
+		if top == nil || scores[n] > scores[top] {
+			top = n
+		}
+	}
+	if top == nil {
+		return "", ErrNoArticle
+	}
+
+	threshold := max(10, scores[top]*0.2)
+	topClass := attr(top, "class")
+	article := []*html.Node{}
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		include := sibling == top
+		if !include {
+			score, ok := scores[sibling]
+			if ok && topClass != "" && attr(sibling, "class") == topClass {
+				score += scores[top] * 0.2
+			}
+			include = ok && score >= threshold
+		}
+		if !include && sibling.DataAtom == atom.P {
+			text := innerText(sibling)
+			density := linkDensity(sibling)
+			include = (len(text) > 80 && density < 0.25) ||
+				(len(text) > 0 && density == 0 && strings.Contains(text, ". "))
+		}
+		if include {
+			article = append(article, sibling)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<div>")
+	for _, n := range article {
+		stripAttributes(n)
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+	buf.WriteString("</div>")
+	return buf.String(), nil
+}
+
+// clean removes the elements that can't be part of the article.
+func clean(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type != html.ElementNode:
+		case removed[c.DataAtom] || isUnlikely(c):
+			n.RemoveChild(c)
+		default:
+			clean(c)
+		}
+		c = next
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	if n.DataAtom == atom.Article || n.DataAtom == atom.Body || n.DataAtom == atom.A {
+		return false
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyPattern.MatchString(match) && !maybePattern.MatchString(match)
+}
+
+// isParagraph reports whether n holds a run of text to score: a paragraph,
+// or a div used as one.
+func isParagraph(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.P, atom.Pre, atom.Td:
+		return true
+	case atom.Div:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && blockElements[c.DataAtom] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func initialScore(n *html.Node) float64 {
+	score := 0.0
+	switch n.DataAtom {
+	case atom.Div, atom.Article:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+	for _, name := range []string{"class", "id"} {
+		value := attr(n, name)
+		if value == "" {
+			continue
+		}
+		if negativePattern.MatchString(value) {
+			score -= 25
+		}
+		if positivePattern.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity is the share of the text of n that is inside links.
+func linkDensity(n *html.Node) float64 {
+	text := len(innerText(n))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	walk(n, func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			links += len(innerText(c))
+		}
+	})
+	return float64(links) / float64(text)
+}
+
+func stripAttributes(n *html.Node) {
+	walk(n, func(c *html.Node) {
+		attrs := c.Attr[:0]
+		for _, a := range c.Attr {
+			if keptAttributes[a.Key] && !strings.HasPrefix(strings.TrimSpace(strings.ToLower(a.Val)), "javascript:") {
+				attrs = append(attrs, a)
+			}
+		}
+		c.Attr = attrs
+	})
+}
+
+// walk calls f for n and all of its descendants, in document order.
+func walk(n *html.Node, f func(*html.Node)) {
+	f(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// innerText is the text of n and its descendants, with runs of whitespace
+// collapsed.
+func innerText(n *html.Node) string {
+	var sb strings.Builder
+	walk(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			sb.WriteString(" ")
+		}
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,170 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+// Actions a rule can take on the posts it matches. Tag actions are "tag:"
+// followed by the name of the tag.
+const (
+	Hide      = "hide"
+	MarkRead  = "mark-read"
+	Star      = "star"
+	TagPrefix = "tag:"
+)
+
+// Rule is a rule with its regular expressions compiled.
+type Rule struct {
+	database.Rule
+	title   *regexp.Regexp
+	content *regexp.Regexp
+}
+
+// ParseAction checks an action and returns it in the form it's stored in, with
+// tag names lowercased like the tag command does.
+func ParseAction(action string) (string, error) {
+	switch action {
+	case Hide, MarkRead, Star:
+		return action, nil
+	}
+	if name, ok := strings.CutPrefix(action, TagPrefix); ok {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return "", fmt.Errorf("missing tag name in action '%s'", action)
+		}
+		return TagPrefix + name, nil
+	}
+	return "", fmt.Errorf("unknown action '%s', expected one of hide, mark-read, star, tag:<name>", action)
+}
+
+// Compile compiles a rule's regular expressions. A rule needs at least one.
+func Compile(r database.Rule) (Rule, error) {
+	rule := Rule{Rule: r}
+	if r.TitleRegex == "" && r.ContentRegex == "" {
+		return rule, fmt.Errorf("a rule needs a title or a content regex")
+	}
+	var err error
+	if r.TitleRegex != "" {
+		if rule.title, err = regexp.Compile(r.TitleRegex); err != nil {
+			return rule, fmt.Errorf("invalid title regex: %w", err)
+		}
+	}
+	if r.ContentRegex != "" {
+		if rule.content, err = regexp.Compile(r.ContentRegex); err != nil {
+			return rule, fmt.Errorf("invalid content regex: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+// Matches reports whether a post of the given feed matches the rule: the rule
+// is for that feed or for all feeds, and each of its regexes matches. The
+// content regex matches if it matches either the description or the content.
+func (r Rule) Matches(feedID uuid.UUID, title, description, content string) bool {
+	if r.FeedID.Valid && r.FeedID.UUID != feedID {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(title) {
+		return false
+	}
+	if r.content != nil && !r.content.MatchString(description) && !r.content.MatchString(content) {
+		return false
+	}
+	return true
+}
+
+// Apply takes the rule's action on a post, for the rule's user. Applying it
+// again changes nothing.
+func (r Rule) Apply(ctx context.Context, q *database.Queries, postID uuid.UUID) error {
+	switch r.Action {
+	case Hide:
+		return q.HidePost(ctx, database.HidePostParams{
+			CreatedAt: time.Now(),
+			UserID:    r.UserID,
+			PostID:    postID,
+		})
+	case MarkRead:
+		return q.MarkPostRead(ctx, database.MarkPostReadParams{
+			CreatedAt: time.Now(),
+			UserID:    r.UserID,
+			PostID:    postID,
+		})
+	case Star:
+		return q.StarPost(ctx, database.StarPostParams{
+			CreatedAt: time.Now(),
+			UserID:    r.UserID,
+			PostID:    postID,
+		})
+	}
+	name, ok := strings.CutPrefix(r.Action, TagPrefix)
+	if !ok {
+		return fmt.Errorf("unknown action '%s'", r.Action)
+	}
+	tag, err := q.CreateTag(ctx, database.CreateTagParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Name:      name,
+		UserID:    r.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	return q.AddPostTag(ctx, database.AddPostTagParams{
+		CreatedAt: time.Now(),
+		PostID:    postID,
+		TagID:     tag.ID,
+	})
+}
+
+// ApplyToNew applies the rules of the users following feed to its new posts.
+// Rules that don't compile anymore are skipped.
+func ApplyToNew(ctx context.Context, q *database.Queries, feed database.Feed, posts []database.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	saved, err := q.GetRulesForFeed(ctx, uuid.NullUUID{UUID: feed.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+	for _, r := range saved {
+		rule, err := Compile(r)
+		if err != nil {
+			continue
+		}
+		for _, p := range posts {
+			if !rule.Matches(p.FeedID, p.Title, p.Description, p.Content) {
+				continue
+			}
+			if err := rule.Apply(ctx, q, p.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Hidden returns the IDs of the posts the user has hidden, so that new posts
+// hidden by ApplyToNew aren't sent to them.
+func Hidden(ctx context.Context, q *database.Queries, userID uuid.UUID, posts []database.Post) (map[uuid.UUID]bool, error) {
+	ids := make([]uuid.UUID, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	hidden, err := q.GetHiddenPostIDs(ctx, database.GetHiddenPostIDsParams{UserID: userID, PostIds: ids})
+	if err != nil {
+		return nil, err
+	}
+	found := map[uuid.UUID]bool{}
+	for _, id := range hidden {
+		found[id] = true
+	}
+	return found, nil
+}
@@ -0,0 +1,261 @@
+package sanitize
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowed are the elements kept in sanitized HTML, with the attributes each
+// may keep besides those in global. Other elements are replaced with their
+// sanitized contents, except those in dropped.
+var allowed = map[atom.Atom][]string{
+	atom.A:          {"href"},
+	atom.Abbr:       nil,
+	atom.Acronym:    nil,
+	atom.Address:    nil,
+	atom.Article:    nil,
+	atom.Aside:      nil,
+	atom.Audio:      {"src", "controls"},
+	atom.B:          nil,
+	atom.Bdi:        nil,
+	atom.Bdo:        nil,
+	atom.Big:        nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Center:     nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Col:        {"span"},
+	atom.Colgroup:   {"span"},
+	atom.Dd:         nil,
+	atom.Del:        {"cite", "datetime"},
+	atom.Details:    {"open"},
+	atom.Dfn:        nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.Footer:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Header:     nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "width", "height"},
+	atom.Ins:        {"cite", "datetime"},
+	atom.Kbd:        nil,
+	atom.Li:         {"value"},
+	atom.Main:       nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start", "reversed", "type"},
+	atom.P:          nil,
+	atom.Picture:    nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.Rp:         nil,
+	atom.Rt:         nil,
+	atom.Ruby:       nil,
+	atom.S:          nil,
+	atom.Samp:       nil,
+	atom.Section:    nil,
+	atom.Small:      nil,
+	atom.Source:     {"src", "type"},
+	atom.Span:       nil,
+	atom.Strike:     nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Summary:    nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan", "headers"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan", "headers", "scope"},
+	atom.Thead:      nil,
+	atom.Time:       {"datetime"},
+	atom.Tr:         nil,
+	atom.Tt:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+	atom.Var:        nil,
+	atom.Video:      {"src", "poster", "controls", "width", "height"},
+	atom.Wbr:        nil,
+}
+
+// global are the attributes any allowed element may keep.
+var global = map[string]bool{
+	"title": true,
+	"lang":  true,
+	"dir":   true,
+}
+
+// links are the attributes holding URLs, which are made absolute and kept
+// only if they're safe to follow.
+var links = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"cite":   true,
+	"poster": true,
+}
+
+// dropped are the elements removed along with their contents, which aren't
+// text to read, or could hide markup the other rules don't expect, like the
+// SVG and MathML elements, whose attributes work differently from HTML's.
+var dropped = map[atom.Atom]bool{
+	atom.Applet:    true,
+	atom.Base:      true,
+	atom.Button:    true,
+	atom.Embed:     true,
+	atom.Form:      true,
+	atom.Frame:     true,
+	atom.Frameset:  true,
+	atom.Head:      true,
+	atom.Iframe:    true,
+	atom.Input:     true,
+	atom.Link:      true,
+	atom.Math:      true,
+	atom.Meta:      true,
+	atom.Noembed:   true,
+	atom.Noframes:  true,
+	atom.Noscript:  true,
+	atom.Object:    true,
+	atom.Param:     true,
+	atom.Plaintext: true,
+	atom.Script:    true,
+	atom.Select:    true,
+	atom.Style:     true,
+	atom.Svg:       true,
+	atom.Template:  true,
+	atom.Textarea:  true,
+	atom.Title:     true,
+	atom.Xmp:       true,
+}
+
+// HTML returns content, a fragment of HTML from a feed or web page, keeping
+// only the elements and attributes known to be safe, and links to http, https
+// and mailto URLs, made absolute using baseURL. Scripts, styles, embedded
+// frames, forms and comments are removed.
+func HTML(content, baseURL string) (string, error) {
+	body, err := Body(content, baseURL)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// Body parses content and sanitizes it like HTML, returning its body element.
+func Body(content, baseURL string) (*html.Node, error) {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	body := find(doc, atom.Body)
+	if body == nil {
+		return nil, fmt.Errorf("couldn't parse the content")
+	}
+	base, _ := url.Parse(baseURL)
+	Node(body, base)
+	return body, nil
+}
+
+// Node sanitizes the descendants of n in place, resolving links against base
+// if it isn't nil.
+func Node(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			attrs, ok := allowed[c.DataAtom]
+			switch {
+			case c.Namespace != "" || dropped[c.DataAtom]:
+				n.RemoveChild(c)
+			case !ok:
+				// Keep the text of unknown elements, like font or nav.
+				Node(c, base)
+				for gc := c.FirstChild; gc != nil; {
+					gnext := gc.NextSibling
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+					gc = gnext
+				}
+				n.RemoveChild(c)
+			default:
+				c.Attr = sanitizeAttrs(c.Attr, attrs, base)
+				Node(c, base)
+			}
+		default:
+			n.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+// sanitizeAttrs returns the attributes in attrs that are global or in names,
+// with their links resolved against base, dropping links that aren't safe.
+func sanitizeAttrs(attrs []html.Attribute, names []string, base *url.URL) []html.Attribute {
+	kept := attrs[:0]
+	for _, a := range attrs {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" || (!global[key] && !slices.Contains(names, key)) {
+			continue
+		}
+		if links[key] {
+			a.Val = resolve(base, a.Val)
+			if a.Val == "" {
+				continue
+			}
+		}
+		a.Key = key
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// resolve makes link absolute, returning "" unless it's a relative link or an
+// http, https or mailto URL.
+func resolve(base *url.URL, link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return u.String()
+	}
+	return ""
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package sanitize
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"text", `<p>Hello, <b>world</b>!</p>`, `<p>Hello, <b>world</b>!</p>`},
+		{"relative links", `<a href="/next" title="Next">next</a> <img src="a.png" alt="A">`, `<a href="https://example.org/next" title="Next">next</a> <img src="https://example.org/posts/a.png" alt="A"/>`},
+		{"mailto", `<a href="mailto:me@example.org">me</a>`, `<a href="mailto:me@example.org">me</a>`},
+		{"unknown attributes", `<p id="x" class="y" style="color: red" data-x="1">p</p>`, `<p>p</p>`},
+		{"event handlers", `<img src="a.png" onerror="alert(1)"><a href="/" onmouseover="alert(1)">a</a>`, `<img src="https://example.org/posts/a.png"/><a href="https://example.org/">a</a>`},
+		{"unknown elements", `<font color="red">red</font> <nav><a href="/">home</a></nav>`, `red <a href="https://example.org/">home</a>`},
+		{"comments", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+		{"javascript href", `<a href="javascript:alert(1)">a</a>`, `<a>a</a>`},
+		{"javascript href with case and spaces", `<a href="  JaVaScRiPt:alert(1)">a</a>`, `<a>a</a>`},
+		{"javascript href with entities", `<a href="java&#x09;script:alert(1)">a</a><a href="&#106;avascript:alert(1)">b</a>`, `<a>a</a><a>b</a>`},
+		{"data href", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">a</a>`, `<a>a</a>`},
+		{"data src", `<img src="data:image/svg+xml,&lt;svg onload=alert(1)&gt;">`, `<img/>`},
+		{"vbscript href", `<a href="vbscript:msgbox(1)">a</a>`, `<a>a</a>`},
+		{"file src", `<img src="file:///etc/passwd">`, `<img/>`},
+		{"cite", `<blockquote cite="javascript:alert(1)">q</blockquote>`, `<blockquote>q</blockquote>`},
+		{"scripts", `<script>alert(1)</script><style>p{}</style>ok`, `ok`},
+		{"frames and objects", `<iframe src="https://example.org/"></iframe><object data="x.swf"></object><embed src="x.swf">ok`, `ok`},
+		{"forms", `<form action="/"><input name="q"><button>Go</button></form>ok`, `ok`},
+		{"base and meta", `<base href="javascript:alert(1)//"><meta http-equiv="refresh" content="0;url=javascript:alert(1)">ok`, `ok`},
+		{"noscript", `<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>ok`, `<img src="https://example.org/posts/x"/>&#34;&gt;ok`},
+		{"template", `<template><img src="x" onerror="alert(1)"></template>ok`, `ok`},
+		{"svg animate", `<svg><a><animate attributeName="href" values="javascript:alert(1)"/><text x="20" y="20">click</text></a></svg>ok`, `ok`},
+		{"svg set", `<svg><a><set attributeName="href" to="javascript:alert(1)"/><text>click</text></a></svg>ok`, `ok`},
+		{"svg script", `<svg><script>alert(1)</script><image href="javascript:alert(1)"/></svg>ok`, `ok`},
+		{"math", `<math><mtext><a xlink:href="javascript:alert(1)">click</a></mtext></math>ok`, `ok`},
+		{"math mXSS", `<math><mtext><table><mglyph><style><img src=x onerror=alert(1)></style></mglyph></table></mtext></math>ok`, `ok`},
+		{"namespaced attributes", `<a xlink:href="javascript:alert(1)" href="/">a</a>`, `<a href="https://example.org/">a</a>`},
+		{"tables", `<table><tr><td colspan="2" onclick="alert(1)">c</td></tr></table>`, `<table><tbody><tr><td colspan="2">c</td></tr></tbody></table>`},
+	}
+	for _, tt := range tests {
+		got, err := HTML(tt.in, "https://example.org/posts/1")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: HTML(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
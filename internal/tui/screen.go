@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"golang.org/x/term"
+)
+
+const (
+	styleReset    = "\x1b[0m"
+	styleReverse  = "\x1b[7m"
+	styleBold     = "\x1b[1m"
+	styleDim      = "\x1b[2m"
+	clearScreen   = "\x1b[2J"
+	clearLine     = "\x1b[K"
+	altScreenOn   = "\x1b[?1049h"
+	altScreenOff  = "\x1b[?1049l"
+	cursorHide    = "\x1b[?25l"
+	cursorShow    = "\x1b[?25h"
+	minimumWidth  = 40
+	minimumHeight = 10
+)
+
+const helpLine = "q quit  tab pane  j/k move  enter open  r read  s star  o browser  R reload"
+
+func (r *reader) enterScreen() {
+	r.out.WriteString(altScreenOn + cursorHide + clearScreen)
+	r.out.Flush()
+}
+
+func (r *reader) leaveScreen() {
+	r.out.WriteString(styleReset + cursorShow + altScreenOff)
+	r.out.Flush()
+}
+
+// resize reads the size of the terminal, and reports whether it changed.
+func (r *reader) resize() bool {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || (width == r.width && height == r.height) {
+		return false
+	}
+	r.width = width
+	r.height = height
+	r.updatePreview()
+	return true
+}
+
+func (r *reader) sourcesWidth() int {
+	return max(12, min(32, r.width/4))
+}
+
+// listHeight is the height of the panes, between the title and status lines.
+func (r *reader) listHeight() int {
+	return r.height - 2
+}
+
+func (r *reader) postsHeight() int {
+	return max(3, r.listHeight()*2/5)
+}
+
+func (r *reader) previewHeight() int {
+	return r.listHeight() - r.postsHeight() - 1
+}
+
+func (r *reader) draw() {
+	out := r.out
+	if r.width < minimumWidth || r.height < minimumHeight {
+		out.WriteString(clearScreen)
+		r.line(1, "Terminal too small")
+		out.Flush()
+		return
+	}
+
+	left := r.sourcesWidth()
+	right := r.width - left - 1
+	src := r.sources[r.source]
+
+	title := fmt.Sprintf(" gator: %s, %s", r.user.Name, strings.TrimSpace(src.label))
+	r.line(1, styleReverse+fit(title, r.width)+styleReset)
+
+	r.sourceTop = scroll(r.sourceTop, r.source, r.listHeight())
+	r.postsTop = scroll(r.postsTop, r.post, r.postsHeight())
+	for row := 0; row < r.listHeight(); row++ {
+		s := strings.Repeat(" ", left)
+		if i := r.sourceTop + row; i < len(r.sources) {
+			s = r.highlight(fit(" "+r.sources[i].label, left), i == r.source, sourcesPane)
+		}
+		s += styleDim + "│" + styleReset
+
+		switch {
+		case row < r.postsHeight():
+			i := r.postsTop + row
+			if i < len(r.posts) {
+				s += r.highlight(fit(r.postLine(i), right), i == r.post, postsPane)
+			} else if i == 0 {
+				s += styleDim + fit(" No posts", right) + styleReset
+			}
+		case row == r.postsHeight():
+			s += styleDim + strings.Repeat("─", right) + styleReset
+		default:
+			i := r.previewTop + row - r.postsHeight() - 1
+			if i < len(r.preview) {
+				text := fit(" "+r.preview[i], right)
+				if i == 0 {
+					text = styleBold + text + styleReset
+				}
+				s += text
+			}
+		}
+		r.line(row+2, s)
+	}
+
+	status := r.status
+	if status == "" {
+		status = helpLine
+	}
+	r.line(r.height, styleDim+fit(" "+status, r.width)+styleReset)
+	out.Flush()
+}
+
+func (r *reader) line(row int, s string) {
+	fmt.Fprintf(r.out, "\x1b[%d;1H%s%s", row, s, clearLine)
+}
+
+// highlight marks the selected line of a pane, more strongly if the pane has
+// the focus.
+func (r *reader) highlight(s string, selected bool, p pane) string {
+	if !selected {
+		return s
+	}
+	if r.focus == p {
+		return styleReverse + s + styleReset
+	}
+	return styleBold + s + styleReset
+}
+
+func (r *reader) postLine(i int) string {
+	post := r.posts[i]
+	read, starred := " ", " "
+	if !post.Read {
+		read = "•"
+	}
+	if post.Starred {
+		starred = "★"
+	}
+	return fmt.Sprintf(" %s%s %s  %s (%s)", read, starred, post.PublishedAt.Format("Jan 02"), htmltext.Clean(post.Title), r.feedNames[post.FeedID])
+}
+
+// scroll returns the first line to show of a list of the given height, so
+// that the selected line is visible.
+func scroll(top, selected, height int) int {
+	if selected < top {
+		return selected
+	}
+	if selected >= top+height {
+		return selected - height + 1
+	}
+	return top
+}
+
+// fit truncates or pads s to width columns.
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// parseKeys splits what was read from the terminal into keys: printable
+// characters as they are, and names such as "up" or "enter" for the others.
+func parseKeys(b []byte) []string {
+	keys := []string{}
+	for len(b) > 0 {
+		if b[0] == 0x1b && len(b) > 2 && (b[1] == '[' || b[1] == 'O') {
+			end := 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			if end == len(b) {
+				end--
+			}
+			keys = append(keys, escapeKeys[string(b[2:end+1])])
+			b = b[end+1:]
+			continue
+		}
+		switch b[0] {
+		case 0x1b:
+			keys = append(keys, "esc")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		default:
+			c, size := utf8.DecodeRune(b)
+			keys = append(keys, string(c))
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+var escapeKeys = map[string]string{
+	"A":  "up",
+	"B":  "down",
+	"C":  "right",
+	"D":  "left",
+	"H":  "home",
+	"F":  "end",
+	"Z":  "shift+tab",
+	"1~": "home",
+	"4~": "end",
+	"5~": "pgup",
+	"6~": "pgdown",
+	"7~": "home",
+	"8~": "end",
+}
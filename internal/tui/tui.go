@@ -0,0 +1,430 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/browser"
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/htmltext"
+	"github.com/google/uuid"
+	"golang.org/x/term"
+)
+
+const postsPageSize = 100
+
+type pane int
+
+const (
+	sourcesPane pane = iota
+	postsPane
+	previewPane
+)
+
+// A source is an entry of the left pane: a set of posts to list.
+type source struct {
+	label   string
+	feed    sql.NullString
+	folder  sql.NullString
+	unread  bool
+	starred bool
+}
+
+type reader struct {
+	ctx  context.Context
+	q    *database.Queries
+	user database.User
+	out  *bufio.Writer
+
+	width  int
+	height int
+	focus  pane
+
+	sources   []source
+	source    int
+	sourceTop int
+	feedNames map[uuid.UUID]string
+
+	posts     []database.GetPostsForUserRow
+	post      int
+	postsTop  int
+	morePosts bool
+
+	preview    []string
+	previewTop int
+
+	status string
+}
+
+// Run shows a full-screen reader of the posts from the feeds user follows,
+// until they quit. Stdin and stdout must be a terminal.
+func Run(ctx context.Context, q *database.Queries, user database.User) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("the reader needs an interactive terminal")
+	}
+
+	r := &reader{
+		ctx:  ctx,
+		q:    q,
+		user: user,
+		out:  bufio.NewWriter(os.Stdout),
+	}
+	if err := r.loadSources(); err != nil {
+		return err
+	}
+	if err := r.loadPosts(); err != nil {
+		return err
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	r.enterScreen()
+	defer func() {
+		r.leaveScreen()
+		term.Restore(fd, oldState)
+	}()
+
+	// Stdin is only read when the loop asks for the next key, so that nothing
+	// is read from it while another program, such as a terminal browser, has
+	// the terminal.
+	keys := make(chan []byte)
+	next := make(chan struct{})
+	defer close(next)
+	go func() {
+		buf := make([]byte, 256)
+		for range next {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	next <- struct{}{}
+
+	// Poll the terminal size rather than waiting for SIGWINCH, which doesn't
+	// exist on every platform.
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	r.resize()
+	r.draw()
+	for {
+		select {
+		case b, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range parseKeys(b) {
+				if key == "q" || key == "ctrl+c" {
+					return nil
+				}
+				r.handleKey(key, fd, oldState)
+			}
+			r.resize()
+			r.draw()
+			next <- struct{}{}
+		case <-ticker.C:
+			if r.resize() {
+				r.draw()
+			}
+		}
+	}
+}
+
+func (r *reader) loadSources() error {
+	feedFollows, err := r.q.GetFeedFollowsForUser(r.ctx, r.user.Name)
+	if err != nil {
+		return err
+	}
+
+	r.sources = []source{
+		{label: "All posts"},
+		{label: "Unread", unread: true},
+		{label: "Starred", starred: true},
+	}
+	r.feedNames = map[uuid.UUID]string{}
+	for i, feedFollow := range feedFollows {
+		name := htmltext.Clean(feedFollow.FeedName)
+		r.feedNames[feedFollow.FeedID] = name
+		feed := source{label: name, feed: sql.NullString{String: feedFollow.FeedUrl, Valid: true}}
+		if !feedFollow.FolderName.Valid {
+			r.sources = append(r.sources, feed)
+			continue
+		}
+		if i == 0 || feedFollow.FolderName != feedFollows[i-1].FolderName {
+			r.sources = append(r.sources, source{label: htmltext.Clean(feedFollow.FolderName.String) + "/", folder: feedFollow.FolderName})
+		}
+		feed.label = "  " + feed.label
+		r.sources = append(r.sources, feed)
+	}
+	r.source = min(r.source, len(r.sources)-1)
+	return nil
+}
+
+// loadPosts lists the posts of the selected source from the start.
+func (r *reader) loadPosts() error {
+	posts, err := r.fetchPosts(uuid.NullUUID{})
+	if err != nil {
+		return err
+	}
+	r.posts = posts
+	r.morePosts = len(posts) == postsPageSize
+	r.post = 0
+	r.postsTop = 0
+	r.updatePreview()
+	return nil
+}
+
+// loadMorePosts appends the next page of posts, once the selection reaches
+// the end of the list.
+func (r *reader) loadMorePosts() error {
+	if !r.morePosts || len(r.posts) == 0 {
+		return nil
+	}
+	last := r.posts[len(r.posts)-1]
+	posts, err := r.fetchPosts(uuid.NullUUID{UUID: last.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+	r.posts = append(r.posts, posts...)
+	r.morePosts = len(posts) == postsPageSize
+	return nil
+}
+
+func (r *reader) fetchPosts(before uuid.NullUUID) ([]database.GetPostsForUserRow, error) {
+	src := r.sources[r.source]
+	return r.q.GetPostsForUser(r.ctx, database.GetPostsForUserParams{
+		UserID:      r.user.ID,
+		Feed:        src.feed,
+		Folder:      src.folder,
+		Sort:        "published",
+		UnreadOnly:  src.unread,
+		StarredOnly: src.starred,
+		Before:      before,
+		Limit:       postsPageSize,
+	})
+}
+
+func (r *reader) selectedPost() (*database.GetPostsForUserRow, bool) {
+	if r.post >= len(r.posts) {
+		return nil, false
+	}
+	return &r.posts[r.post], true
+}
+
+func (r *reader) setRead(post *database.GetPostsForUserRow, read bool) error {
+	var err error
+	if read {
+		err = r.q.MarkPostRead(r.ctx, database.MarkPostReadParams{
+			CreatedAt: time.Now(),
+			UserID:    r.user.ID,
+			PostID:    post.ID,
+		})
+	} else {
+		err = r.q.MarkPostUnread(r.ctx, database.MarkPostUnreadParams{
+			UserID: r.user.ID,
+			PostID: post.ID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	post.Read = read
+	return nil
+}
+
+func (r *reader) setStarred(post *database.GetPostsForUserRow, starred bool) error {
+	var err error
+	if starred {
+		err = r.q.StarPost(r.ctx, database.StarPostParams{
+			CreatedAt: time.Now(),
+			UserID:    r.user.ID,
+			PostID:    post.ID,
+		})
+	} else {
+		err = r.q.UnstarPost(r.ctx, database.UnstarPostParams{
+			UserID: r.user.ID,
+			PostID: post.ID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	post.Starred = starred
+	return nil
+}
+
+func (r *reader) handleKey(key string, fd int, oldState *term.State) {
+	r.status = ""
+	if err := r.doKey(key, fd, oldState); err != nil {
+		r.status = "Error: " + err.Error()
+	}
+}
+
+func (r *reader) doKey(key string, fd int, oldState *term.State) error {
+	switch key {
+	case "tab":
+		r.focus = (r.focus + 1) % 3
+		return nil
+	case "shift+tab":
+		r.focus = (r.focus + 2) % 3
+		return nil
+	case "h", "left":
+		if r.focus > sourcesPane {
+			r.focus--
+		}
+		return nil
+	case "l", "right":
+		if r.focus < previewPane {
+			r.focus++
+		}
+		return nil
+	case "R":
+		if err := r.loadSources(); err != nil {
+			return err
+		}
+		if err := r.loadPosts(); err != nil {
+			return err
+		}
+		r.status = "Reloaded"
+		return nil
+	}
+
+	switch r.focus {
+	case sourcesPane:
+		selected := r.source
+		switch key {
+		case "j", "down":
+			selected++
+		case "k", "up":
+			selected--
+		case "pgdown", " ":
+			selected += r.listHeight()
+		case "pgup":
+			selected -= r.listHeight()
+		case "g", "home":
+			selected = 0
+		case "G", "end":
+			selected = len(r.sources) - 1
+		case "enter":
+			r.focus = postsPane
+			return nil
+		}
+		selected = max(0, min(selected, len(r.sources)-1))
+		if selected != r.source {
+			r.source = selected
+			return r.loadPosts()
+		}
+		return nil
+	case postsPane:
+		selected := r.post
+		switch key {
+		case "j", "down":
+			selected++
+		case "k", "up":
+			selected--
+		case "pgdown", " ":
+			selected += r.postsHeight()
+		case "pgup":
+			selected -= r.postsHeight()
+		case "g", "home":
+			selected = 0
+		case "G", "end":
+			selected = len(r.posts) - 1
+		case "enter":
+			post, ok := r.selectedPost()
+			if !ok {
+				return nil
+			}
+			r.focus = previewPane
+			return r.setRead(post, true)
+		}
+		if selected >= len(r.posts)-1 {
+			if err := r.loadMorePosts(); err != nil {
+				return err
+			}
+		}
+		selected = max(0, min(selected, len(r.posts)-1))
+		if selected != r.post {
+			r.post = selected
+			r.updatePreview()
+		}
+	case previewPane:
+		switch key {
+		case "j", "down":
+			r.scrollPreview(1)
+		case "k", "up":
+			r.scrollPreview(-1)
+		case "pgdown", " ":
+			r.scrollPreview(r.previewHeight() - 1)
+		case "pgup":
+			r.scrollPreview(-(r.previewHeight() - 1))
+		case "g", "home":
+			r.previewTop = 0
+		case "G", "end":
+			r.scrollPreview(len(r.preview))
+		case "esc":
+			r.focus = postsPane
+		}
+	}
+
+	post, ok := r.selectedPost()
+	if !ok {
+		return nil
+	}
+	switch key {
+	case "r":
+		return r.setRead(post, !post.Read)
+	case "s":
+		return r.setStarred(post, !post.Starred)
+	case "o":
+		r.leaveScreen()
+		term.Restore(fd, oldState)
+		err := browser.Open(post.Url)
+		if _, rawErr := term.MakeRaw(fd); rawErr != nil && err == nil {
+			err = rawErr
+		}
+		r.enterScreen()
+		if err != nil {
+			return err
+		}
+		return r.setRead(post, true)
+	}
+	return nil
+}
+
+func (r *reader) updatePreview() {
+	r.preview = nil
+	r.previewTop = 0
+	post, ok := r.selectedPost()
+	if !ok {
+		return
+	}
+	width := r.width - r.sourcesWidth() - 3
+	body := post.Content
+	if body == "" {
+		body = post.Description
+	}
+	r.preview = append(r.preview, htmltext.Wrap(htmltext.Clean(post.Title), width)...)
+	r.preview = append(r.preview,
+		"",
+		fmt.Sprintf("%s, %s", r.feedNames[post.FeedID], post.PublishedAt.Format("January 2, 2006 15:04")),
+		htmltext.Clean(post.Url),
+		"",
+	)
+	r.preview = append(r.preview, strings.Split(htmltext.Render(body, post.Url, width), "\n")...)
+}
+
+func (r *reader) scrollPreview(lines int) {
+	r.previewTop = max(0, min(r.previewTop+lines, len(r.preview)-r.previewHeight()))
+}
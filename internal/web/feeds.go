@@ -0,0 +1,100 @@
+package web
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/google/uuid"
+)
+
+type feedItem struct {
+	Name   string
+	URL    string
+	Folder string
+}
+
+// feeds lists the feeds the user follows, and the other feeds in the
+// database they can follow.
+func (s *Server) feeds(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollows, err := s.q.GetFeedFollowsForUser(r.Context(), user.Name)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	feeds, err := s.q.GetFeeds(r.Context())
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+
+	following := []feedItem{}
+	followed := map[uuid.UUID]bool{}
+	for _, feedFollow := range feedFollows {
+		followed[feedFollow.FeedID] = true
+		following = append(following, feedItem{feedFollow.FeedName, feedFollow.FeedUrl, feedFollow.FolderName.String})
+	}
+	others := []feedItem{}
+	for _, feed := range feeds {
+		if !followed[feed.ID] {
+			others = append(others, feedItem{Name: feed.Name, URL: feed.Url})
+		}
+	}
+
+	s.render(w, http.StatusOK, "feeds.html", page{Title: "Feeds", User: &user, Data: struct {
+		Following []feedItem
+		Others    []feedItem
+	}{following, others}})
+}
+
+// follow follows a feed in the database by its URL.
+func (s *Server) follow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedURL := strings.TrimSpace(r.FormValue("url"))
+	feed, err := s.q.GetFeed(r.Context(), feedURL)
+	if err == sql.ErrNoRows {
+		s.renderError(w, http.StatusNotFound, "There's no feed with the URL '"+feedURL+"'. Add it with gator addfeed first.")
+		return
+	}
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	_, err = s.q.GetFeedFollow(r.Context(), database.GetFeedFollowParams{
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err == nil {
+		redirectBack(w, r, "/feeds")
+		return
+	}
+	if err != sql.ErrNoRows {
+		s.internalError(w, r, err)
+		return
+	}
+	_, err = s.q.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	redirectBack(w, r, "/feeds")
+}
+
+func (s *Server) unfollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	err := s.q.DeleteFeedFollow(r.Context(), database.DeleteFeedFollowParams{
+		UserID: user.ID,
+		Url:    r.FormValue("url"),
+	})
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	redirectBack(w, r, "/feeds")
+}
@@ -0,0 +1,268 @@
+package web
+
+import (
+	"database/sql"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/sanitize"
+	"github.com/google/uuid"
+)
+
+const postsPageSize = 30
+
+// A source is an entry of the sidebar: a set of posts to list.
+type source struct {
+	Label   string
+	URL     string
+	Current bool
+	Nested  bool
+}
+
+type postItem struct {
+	ID          uuid.UUID
+	Title       string
+	URL         string
+	Feed        string
+	PublishedAt time.Time
+	Read        bool
+	Starred     bool
+}
+
+// timeline lists the posts of the feeds the user follows, newest first,
+// optionally from one feed or folder, or only the unread or starred ones.
+func (s *Server) timeline(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+	filter := url.Values{}
+	for _, key := range []string{"feed", "folder", "unread", "starred"} {
+		if v := query.Get(key); v != "" {
+			filter.Set(key, v)
+		}
+	}
+	before := uuid.NullUUID{}
+	if v := query.Get("before"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			s.renderError(w, http.StatusBadRequest, "Invalid page.")
+			return
+		}
+		before = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	feedFollows, err := s.q.GetFeedFollowsForUser(r.Context(), user.Name)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	sources, feedNames, title := buildSources(feedFollows, filter)
+
+	rows, err := s.q.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+		UserID:      user.ID,
+		Feed:        optionalString(filter.Get("feed")),
+		Folder:      optionalString(filter.Get("folder")),
+		Sort:        "published",
+		UnreadOnly:  filter.Get("unread") != "",
+		StarredOnly: filter.Get("starred") != "",
+		Before:      before,
+		Limit:       postsPageSize,
+	})
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+
+	posts := []postItem{}
+	for _, row := range rows {
+		posts = append(posts, postItem{
+			ID:          row.ID,
+			Title:       row.Title,
+			URL:         row.Url,
+			Feed:        feedNames[row.FeedID],
+			PublishedAt: row.PublishedAt,
+			Read:        row.Read,
+			Starred:     row.Starred,
+		})
+	}
+	older := ""
+	if len(rows) == postsPageSize {
+		next := url.Values{}
+		for key, values := range filter {
+			next[key] = values
+		}
+		next.Set("before", rows[len(rows)-1].ID.String())
+		older = "/?" + next.Encode()
+	}
+
+	s.render(w, http.StatusOK, "timeline.html", page{Title: title, User: &user, Data: struct {
+		Sources []source
+		Posts   []postItem
+		Older   string
+		Here    string
+	}{sources, posts, older, r.URL.RequestURI()}})
+}
+
+// buildSources lists the sidebar entries, marking the one matching filter,
+// whose label is returned as the title of the page.
+func buildSources(feedFollows []database.GetFeedFollowsForUserRow, filter url.Values) ([]source, map[uuid.UUID]string, string) {
+	current := filter.Encode()
+	title := "All posts"
+	sources := []source{}
+	add := func(label string, nested bool, values url.Values) {
+		link := "/"
+		if len(values) > 0 {
+			link += "?" + values.Encode()
+		}
+		isCurrent := values.Encode() == current
+		if isCurrent {
+			title = label
+		}
+		sources = append(sources, source{label, link, isCurrent, nested})
+	}
+
+	add("All posts", false, url.Values{})
+	add("Unread", false, url.Values{"unread": {"1"}})
+	add("Starred", false, url.Values{"starred": {"1"}})
+	feedNames := map[uuid.UUID]string{}
+	for i, feedFollow := range feedFollows {
+		feedNames[feedFollow.FeedID] = feedFollow.FeedName
+		feed := url.Values{"feed": {feedFollow.FeedUrl}}
+		if !feedFollow.FolderName.Valid {
+			add(feedFollow.FeedName, false, feed)
+			continue
+		}
+		if i == 0 || feedFollow.FolderName != feedFollows[i-1].FolderName {
+			add(feedFollow.FolderName.String+"/", false, url.Values{"folder": {feedFollow.FolderName.String}})
+		}
+		add(feedFollow.FeedName, true, feed)
+	}
+	return sources, feedNames, title
+}
+
+// post shows a post, and marks it read like opening it in the terminal does.
+func (s *Server) post(w http.ResponseWriter, r *http.Request, user database.User) {
+	post, ok := s.pathPost(w, r)
+	if !ok {
+		return
+	}
+	state, err := s.q.GetPostState(r.Context(), database.GetPostStateParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	err = s.q.MarkPostRead(r.Context(), database.MarkPostReadParams{
+		CreatedAt: time.Now(),
+		UserID:    user.ID,
+		PostID:    post.ID,
+	})
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	feed, err := s.q.GetFeedByID(r.Context(), post.FeedID)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+
+	content := post.Content
+	if content == "" {
+		content = post.Description
+	}
+	body, err := sanitize.HTML(content, post.Url)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+
+	s.render(w, http.StatusOK, "post.html", page{Title: post.Title, User: &user, Data: struct {
+		Post    postItem
+		Body    template.HTML
+		Back    string
+		FeedURL string
+	}{
+		Post: postItem{
+			ID:          post.ID,
+			Title:       post.Title,
+			URL:         post.Url,
+			Feed:        feed.Name,
+			PublishedAt: post.PublishedAt,
+			Read:        true,
+			Starred:     state.Starred,
+		},
+		Body:    template.HTML(body),
+		Back:    "/?" + url.Values{"feed": {feed.Url}}.Encode(),
+		FeedURL: feed.Url,
+	}})
+}
+
+// setPostState marks a post read or unread, or stars or unstars it, from the
+// form's value field.
+func (s *Server) setPostState(w http.ResponseWriter, r *http.Request, user database.User) {
+	post, ok := s.pathPost(w, r)
+	if !ok {
+		return
+	}
+	set := r.FormValue("value") == "true"
+	var err error
+	if strings.HasSuffix(r.URL.Path, "/read") {
+		if set {
+			err = s.q.MarkPostRead(r.Context(), database.MarkPostReadParams{
+				CreatedAt: time.Now(),
+				UserID:    user.ID,
+				PostID:    post.ID,
+			})
+		} else {
+			err = s.q.MarkPostUnread(r.Context(), database.MarkPostUnreadParams{
+				UserID: user.ID,
+				PostID: post.ID,
+			})
+		}
+	} else {
+		if set {
+			err = s.q.StarPost(r.Context(), database.StarPostParams{
+				CreatedAt: time.Now(),
+				UserID:    user.ID,
+				PostID:    post.ID,
+			})
+		} else {
+			err = s.q.UnstarPost(r.Context(), database.UnstarPostParams{
+				UserID: user.ID,
+				PostID: post.ID,
+			})
+		}
+	}
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	redirectBack(w, r, "/posts/"+post.ID.String())
+}
+
+func (s *Server) pathPost(w http.ResponseWriter, r *http.Request) (database.Post, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.renderError(w, http.StatusNotFound, "Post not found.")
+		return database.Post{}, false
+	}
+	post, err := s.q.GetPost(r.Context(), id)
+	if err == sql.ErrNoRows {
+		s.renderError(w, http.StatusNotFound, "Post not found.")
+		return post, false
+	}
+	if err != nil {
+		s.internalError(w, r, err)
+		return post, false
+	}
+	return post, true
+}
+
+func optionalString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,238 @@
+package web
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+)
+
+//go:embed templates static
+var files embed.FS
+
+const (
+	sessionCookie = "gator_session"
+	sessionMaxAge = 30 * 24 * time.Hour
+)
+
+// contentSecurityPolicy keeps scripts in posts from running even if they get
+// past sanitizing: the UI has no scripts of its own.
+const contentSecurityPolicy = "default-src 'none'; img-src * data:; style-src 'self'; " +
+	"form-action 'self'; frame-ancestors 'none'; base-uri 'none'"
+
+// Server serves a web reader for the posts of the feeds a user follows. Users
+// log in with their name, and with the server's token if it has one.
+type Server struct {
+	q      *database.Queries
+	token  string
+	secret []byte
+	pages  map[string]*template.Template
+	mux    *http.ServeMux
+}
+
+// New returns a web UI server using q. Sessions are signed with a key made
+// when the server starts, so restarting it logs everyone out.
+func New(q *database.Queries, token string) *Server {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	s := &Server{q: q, token: token, secret: secret, pages: parsePages(), mux: http.NewServeMux()}
+
+	static, _ := fs.Sub(files, "static")
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	s.mux.HandleFunc("GET /login", s.loginPage)
+	s.mux.HandleFunc("POST /login", s.login)
+	s.mux.HandleFunc("POST /logout", s.logout)
+	s.mux.HandleFunc("GET /{$}", s.loggedIn(s.timeline))
+	s.mux.HandleFunc("GET /posts/{id}", s.loggedIn(s.post))
+	s.mux.HandleFunc("POST /posts/{id}/read", s.loggedIn(s.setPostState))
+	s.mux.HandleFunc("POST /posts/{id}/star", s.loggedIn(s.setPostState))
+	s.mux.HandleFunc("GET /feeds", s.loggedIn(s.feeds))
+	s.mux.HandleFunc("POST /feeds/follow", s.loggedIn(s.follow))
+	s.mux.HandleFunc("POST /feeds/unfollow", s.loggedIn(s.unfollow))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		s.renderError(w, http.StatusNotFound, "Page not found.")
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if r.Method == http.MethodPost && !sameOrigin(r) {
+		s.renderError(w, http.StatusForbidden, "Cross-origin requests aren't allowed.")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// sameOrigin reports whether a request came from one of the server's pages,
+// as far as the browser says. Session cookies are also SameSite, this guards
+// older browsers.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "null" {
+		return r.Header.Get("Sec-Fetch-Site") != "cross-site"
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+func parsePages() map[string]*template.Template {
+	funcs := template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.Local().Format("Jan 2, 2006 15:04")
+		},
+	}
+	layout := template.Must(template.New("layout.html").Funcs(funcs).ParseFS(files, "templates/layout.html"))
+	pages := map[string]*template.Template{}
+	names, _ := fs.Glob(files, "templates/*.html")
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "templates/")
+		if name == "layout.html" {
+			continue
+		}
+		pages[name] = template.Must(template.Must(layout.Clone()).ParseFS(files, "templates/"+name))
+	}
+	return pages
+}
+
+// page is the data every page is rendered with.
+type page struct {
+	Title string
+	User  *database.User
+	Data  any
+}
+
+func (s *Server) render(w http.ResponseWriter, status int, name string, p page) {
+	var buf bytes.Buffer
+	if err := s.pages[name].Execute(&buf, p); err != nil {
+		log.Printf("Couldn't render %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+func (s *Server) renderError(w http.ResponseWriter, status int, message string) {
+	s.render(w, status, "error.html", page{Title: http.StatusText(status), Data: message})
+}
+
+// internalError logs err and shows a generic error page.
+func (s *Server) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	s.renderError(w, http.StatusInternalServerError, "Something went wrong, try again later.")
+}
+
+// redirectBack redirects to the page given in the form's next field, or to
+// fallback if it isn't a path on this server.
+func redirectBack(w http.ResponseWriter, r *http.Request, fallback string) {
+	next := r.FormValue("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		next = fallback
+	}
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+type loggedInHandler func(w http.ResponseWriter, r *http.Request, user database.User)
+
+// loggedIn runs handler with the user of the request's session, sending
+// visitors without one to the login page.
+func (s *Server) loggedIn(handler loggedInHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, ok := s.sessionUser(r)
+		if !ok {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		user, err := s.q.GetUser(r.Context(), name)
+		if err != nil {
+			clearSession(w)
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		handler(w, r, user)
+	}
+}
+
+func (s *Server) sign(name string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(name))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Server) sessionUser(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+	encoded, signature, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return "", false
+	}
+	name, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(string(name)))) {
+		return "", false
+	}
+	return string(name), true
+}
+
+func (s *Server) loginPage(w http.ResponseWriter, r *http.Request) {
+	s.renderLogin(w, http.StatusOK, "")
+}
+
+func (s *Server) renderLogin(w http.ResponseWriter, status int, message string) {
+	s.render(w, status, "login.html", page{Title: "Log in", Data: struct {
+		NeedToken bool
+		Error     string
+	}{s.token != "", message}})
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.FormValue("name"))
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(r.FormValue("token")), []byte(s.token)) != 1 {
+		s.renderLogin(w, http.StatusUnauthorized, "Wrong token.")
+		return
+	}
+	user, err := s.q.GetUser(r.Context(), name)
+	if err != nil {
+		s.renderLogin(w, http.StatusUnauthorized, "There's no user called '"+name+"'. Register with gator register first.")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(user.Name)) + "." + s.sign(user.Name),
+		Path:     "/",
+		MaxAge:   int(sessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	clearSession(w)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func clearSession(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1})
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/R0Xps/gatorcli/internal/database"
+	"github.com/R0Xps/gatorcli/internal/rules"
+	"github.com/google/uuid"
+)
+
+// Events webhooks are sent for.
+const (
+	PostsCreated = "posts.created"
+	AlertCreated = "alert.created"
+	Ping         = "ping"
+)
+
+const (
+	// maxAttempts is the number of times a delivery is tried before giving up.
+	maxAttempts = 6
+	// duePageSize is the number of due deliveries sent at once.
+	duePageSize = 50
+	timeout     = 10 * time.Second
+)
+
+// backoff is how long to wait before retrying a delivery after each failed
+// attempt.
+var backoff = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+// Payload is the JSON body of a webhook request.
+type Payload struct {
+	Event     string    `json:"event"`
+	WebhookID uuid.UUID `json:"webhook_id"`
+	Feed      *Feed     `json:"feed,omitempty"`
+	Posts     []Post    `json:"posts,omitempty"`
+	Alert     *Alert    `json:"alert,omitempty"`
+}
+
+type Feed struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	URL     string    `json:"url"`
+	SiteURL string    `json:"site_url"`
+}
+
+type Post struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	PublishedAt time.Time `json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Alert is a watch word found in a post. The post is the payload's only post.
+type Alert struct {
+	ID   uuid.UUID `json:"id"`
+	Word string    `json:"word"`
+	// Snippet is the text around the matches, which are wrapped in "**".
+	Snippet string `json:"snippet"`
+}
+
+// NewSecret returns a random secret to sign a webhook's requests with.
+func NewSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Sign returns the X-Gator-Signature header of a request with the given body:
+// "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Queue adds a delivery of the new posts of feed to each webhook that wants
+// them: the ones for the feed, and the ones for all the feeds their user
+// follows. Posts the webhook's user has hidden are left out. They're sent by
+// the next call to DeliverDue.
+func Queue(ctx context.Context, q *database.Queries, feed database.Feed, posts []database.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	webhooks, err := q.GetWebhooksForFeed(ctx, uuid.NullUUID{UUID: feed.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+	hiddenByUser := map[uuid.UUID]map[uuid.UUID]bool{}
+	for _, w := range webhooks {
+		hidden, ok := hiddenByUser[w.UserID]
+		if !ok {
+			if hidden, err = rules.Hidden(ctx, q, w.UserID, posts); err != nil {
+				return err
+			}
+			hiddenByUser[w.UserID] = hidden
+		}
+		payload := Payload{
+			Event:     PostsCreated,
+			WebhookID: w.ID,
+			Feed:      &Feed{ID: feed.ID, Name: feed.Name, URL: feed.Url, SiteURL: feed.SiteUrl},
+		}
+		for _, p := range posts {
+			if !hidden[p.ID] {
+				payload.Posts = append(payload.Posts, newPost(p))
+			}
+		}
+		if len(payload.Posts) == 0 {
+			continue
+		}
+		if _, err := create(ctx, q, w, payload, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueueAlert adds a delivery of an alert about a post of feed to each of the
+// user's webhooks that wants the feed's posts.
+func QueueAlert(ctx context.Context, q *database.Queries, userID uuid.UUID, feed database.Feed, post database.Post, alert Alert) error {
+	webhooks, err := q.GetWebhooksForFeed(ctx, uuid.NullUUID{UUID: feed.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		if w.UserID != userID {
+			continue
+		}
+		payload := Payload{
+			Event:     AlertCreated,
+			WebhookID: w.ID,
+			Feed:      &Feed{ID: feed.ID, Name: feed.Name, URL: feed.Url, SiteURL: feed.SiteUrl},
+			Posts:     []Post{newPost(post)},
+			Alert:     &alert,
+		}
+		if _, err := create(ctx, q, w, payload, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Test sends a ping to a webhook right away, and returns its delivery. Failed
+// pings aren't retried.
+func Test(ctx context.Context, q *database.Queries, w database.Webhook) (database.WebhookDelivery, error) {
+	delivery, err := create(ctx, q, w, Payload{Event: Ping, WebhookID: w.ID}, time.Time{})
+	if err != nil {
+		return delivery, err
+	}
+	return attempt(ctx, q, delivery, w.Url, w.Secret, false)
+}
+
+// DeliverDue sends the deliveries whose next attempt is due, scheduling
+// failed ones to be retried later until they've been tried maxAttempts times.
+// Failures are only logged.
+func DeliverDue(ctx context.Context, q *database.Queries) error {
+	for {
+		due, err := q.GetDueWebhookDeliveries(ctx, database.GetDueWebhookDeliveriesParams{
+			Now:   time.Now(),
+			Limit: duePageSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, d := range due {
+			delivery := database.WebhookDelivery{
+				ID:            d.ID,
+				CreatedAt:     d.CreatedAt,
+				UpdatedAt:     d.UpdatedAt,
+				WebhookID:     d.WebhookID,
+				Event:         d.Event,
+				Payload:       d.Payload,
+				Attempts:      d.Attempts,
+				StatusCode:    d.StatusCode,
+				LastError:     d.LastError,
+				NextAttemptAt: d.NextAttemptAt,
+				DeliveredAt:   d.DeliveredAt,
+			}
+			delivery, err := attempt(ctx, q, delivery, d.Url, d.Secret, true)
+			if err != nil {
+				return err
+			}
+			if !delivery.DeliveredAt.Valid {
+				log.Printf("Couldn't deliver webhook to %s (attempt %d): %s", d.Url, delivery.Attempts, delivery.LastError)
+			}
+		}
+		if len(due) < duePageSize {
+			return nil
+		}
+	}
+}
+
+func newPost(p database.Post) Post {
+	return Post{
+		ID:          p.ID,
+		Title:       p.Title,
+		URL:         p.Url,
+		Description: p.Description,
+		PublishedAt: p.PublishedAt,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+func create(ctx context.Context, q *database.Queries, w database.Webhook, payload Payload, due time.Time) (database.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.CreateWebhookDelivery(ctx, database.CreateWebhookDeliveryParams{
+		ID:            uuid.New(),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		WebhookID:     w.ID,
+		Event:         payload.Event,
+		Payload:       string(body),
+		NextAttemptAt: sql.NullTime{Time: due, Valid: !due.IsZero()},
+	})
+}
+
+// attempt sends a delivery and records the result. Errors from the webhook's
+// server are recorded, only database errors are returned.
+func attempt(ctx context.Context, q *database.Queries, d database.WebhookDelivery, url, secret string, retry bool) (database.WebhookDelivery, error) {
+	status, err := send(ctx, url, secret, d)
+	d.Attempts++
+	d.UpdatedAt = time.Now()
+	d.StatusCode = sql.NullInt32{Int32: int32(status), Valid: status != 0}
+	d.LastError = ""
+	d.NextAttemptAt = sql.NullTime{}
+	if err == nil {
+		d.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	} else {
+		d.LastError = err.Error()
+		if retry && int(d.Attempts) < maxAttempts {
+			d.NextAttemptAt = sql.NullTime{Time: time.Now().Add(backoff[d.Attempts-1]), Valid: true}
+		}
+	}
+	return d, q.UpdateWebhookDelivery(ctx, database.UpdateWebhookDeliveryParams{
+		ID:            d.ID,
+		UpdatedAt:     d.UpdatedAt,
+		Attempts:      d.Attempts,
+		StatusCode:    d.StatusCode,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+	})
+}
+
+// send POSTs a delivery's payload, returning the response's status code if
+// there's one. Anything but a 2xx response is an error.
+func send(ctx context.Context, url, secret string, d database.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gator")
+	req.Header.Set("X-Gator-Event", d.Event)
+	req.Header.Set("X-Gator-Delivery", d.ID.String())
+	req.Header.Set("X-Gator-Signature", Sign(secret, body))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
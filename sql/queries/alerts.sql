@@ -0,0 +1,71 @@
+-- name: CreateWatchWord :one
+INSERT INTO watch_words (id, created_at, updated_at, user_id, word, regex, notify_webhooks, notify_email)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+ON CONFLICT (user_id, word) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    regex = EXCLUDED.regex,
+    notify_webhooks = EXCLUDED.notify_webhooks,
+    notify_email = EXCLUDED.notify_email
+RETURNING *;
+
+-- name: GetWatchWordsForUser :many
+SELECT * FROM watch_words
+WHERE user_id = $1
+ORDER BY word;
+
+-- name: GetWatchWordsForFeed :many
+SELECT watch_words.*
+FROM watch_words
+JOIN feed_follows
+ON watch_words.user_id = feed_follows.user_id
+WHERE feed_follows.feed_id = $1
+ORDER BY watch_words.created_at;
+
+-- name: DeleteWatchWord :execrows
+DELETE FROM watch_words
+WHERE user_id = $1 AND word = $2;
+
+-- name: CreateAlert :execrows
+INSERT INTO alerts (id, created_at, watch_word_id, post_id, snippet)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetAlertsForUser :many
+SELECT
+    alerts.*,
+    watch_words.word,
+    posts.title AS post_title,
+    posts.url AS post_url,
+    feeds.name AS feed_name
+FROM alerts
+JOIN watch_words
+ON alerts.watch_word_id = watch_words.id
+JOIN posts
+ON alerts.post_id = posts.id
+JOIN feeds
+ON posts.feed_id = feeds.id
+WHERE watch_words.user_id = sqlc.arg('user_id')
+AND (sqlc.narg('word')::text IS NULL OR watch_words.word = sqlc.narg('word'))
+ORDER BY alerts.created_at DESC, alerts.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteAlertsForUser :execrows
+DELETE FROM alerts
+USING watch_words
+WHERE alerts.watch_word_id = watch_words.id
+AND watch_words.user_id = $1;
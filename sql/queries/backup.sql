@@ -0,0 +1,270 @@
+-- name: GetAllFolders :many
+SELECT *
+FROM folders
+ORDER BY created_at, id;
+
+-- name: GetAllFeedFollows :many
+SELECT *
+FROM feed_follows
+ORDER BY created_at, id;
+
+-- name: GetAllTags :many
+SELECT *
+FROM tags
+ORDER BY created_at, id;
+
+-- name: GetAllPostTags :many
+SELECT *
+FROM post_tags
+ORDER BY created_at, post_id, tag_id;
+
+-- name: GetPostsPage :many
+SELECT *
+FROM posts
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: RestoreUser :one
+INSERT INTO users (id, created_at, updated_at, name, email)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (name) DO UPDATE
+SET updated_at = users.updated_at
+RETURNING id;
+
+-- name: RestoreFeed :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, last_fetched_at, site_url, full_text)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (url) DO UPDATE
+SET updated_at = feeds.updated_at
+RETURNING id;
+
+-- name: RestoreFolder :one
+INSERT INTO folders (id, created_at, updated_at, name, user_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, name) DO UPDATE
+SET updated_at = folders.updated_at
+RETURNING id;
+
+-- name: RestoreFeedFollow :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id, folder_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (user_id, feed_id) DO UPDATE
+SET folder_id = COALESCE(feed_follows.folder_id, EXCLUDED.folder_id);
+
+-- name: RestorePost :one
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+ON CONFLICT (url) DO UPDATE
+SET updated_at = posts.updated_at
+RETURNING id;
+
+-- name: RestoreTag :one
+INSERT INTO tags (id, created_at, updated_at, name, user_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, name) DO UPDATE
+SET updated_at = tags.updated_at
+RETURNING id;
+
+-- name: RestorePostTag :exec
+INSERT INTO post_tags (created_at, post_id, tag_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetAllPostReads :many
+SELECT *
+FROM post_reads
+ORDER BY created_at, user_id, post_id;
+
+-- name: GetAllPostStars :many
+SELECT *
+FROM post_stars
+ORDER BY created_at, user_id, post_id;
+
+-- name: GetAllPostArchives :many
+SELECT *
+FROM post_archives
+ORDER BY created_at, id;
+
+-- name: RestorePostArchive :exec
+INSERT INTO post_archives (id, created_at, updated_at, user_id, post_id, format, path)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: GetAllFeverKeys :many
+SELECT *
+FROM fever_keys
+ORDER BY created_at, user_id;
+
+-- name: RestoreFeverKey :exec
+INSERT INTO fever_keys (user_id, created_at, updated_at, api_key)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT DO NOTHING;
+
+-- name: GetAllWebhooks :many
+SELECT *
+FROM webhooks
+ORDER BY created_at, id;
+
+-- name: RestoreWebhook :exec
+INSERT INTO webhooks (id, created_at, updated_at, user_id, feed_id, url, secret)
+SELECT
+    sqlc.arg('id')::uuid,
+    sqlc.arg('created_at')::timestamp,
+    sqlc.arg('updated_at')::timestamp,
+    sqlc.arg('user_id')::uuid,
+    sqlc.narg('feed_id')::uuid,
+    sqlc.arg('url')::text,
+    sqlc.arg('secret')::text
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM webhooks
+    WHERE webhooks.user_id = sqlc.arg('user_id')
+    AND webhooks.feed_id IS NOT DISTINCT FROM sqlc.narg('feed_id')
+    AND webhooks.url = sqlc.arg('url')
+);
+
+-- name: GetAllRules :many
+SELECT *
+FROM rules
+ORDER BY created_at, id;
+
+-- name: RestoreRule :exec
+INSERT INTO rules (id, created_at, updated_at, user_id, feed_id, title_regex, content_regex, action)
+SELECT
+    sqlc.arg('id')::uuid,
+    sqlc.arg('created_at')::timestamp,
+    sqlc.arg('updated_at')::timestamp,
+    sqlc.arg('user_id')::uuid,
+    sqlc.narg('feed_id')::uuid,
+    sqlc.arg('title_regex')::text,
+    sqlc.arg('content_regex')::text,
+    sqlc.arg('action')::text
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM rules
+    WHERE rules.user_id = sqlc.arg('user_id')
+    AND rules.feed_id IS NOT DISTINCT FROM sqlc.narg('feed_id')
+    AND rules.title_regex = sqlc.arg('title_regex')
+    AND rules.content_regex = sqlc.arg('content_regex')
+    AND rules.action = sqlc.arg('action')
+);
+
+-- name: GetAllPostHides :many
+SELECT *
+FROM post_hides
+ORDER BY created_at, user_id, post_id;
+
+-- name: GetAllWatchWords :many
+SELECT *
+FROM watch_words
+ORDER BY created_at, id;
+
+-- name: RestoreWatchWord :one
+INSERT INTO watch_words (id, created_at, updated_at, user_id, word, regex, notify_webhooks, notify_email)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+ON CONFLICT (user_id, word) DO UPDATE
+SET updated_at = watch_words.updated_at
+RETURNING id;
+
+-- name: GetAllAlerts :many
+SELECT *
+FROM alerts
+ORDER BY created_at, id;
+
+-- name: RestoreAlert :exec
+INSERT INTO alerts (id, created_at, watch_word_id, post_id, snippet)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (watch_word_id, post_id) DO NOTHING;
+
+-- name: GetAllFeedTokens :many
+SELECT *
+FROM feed_tokens
+ORDER BY created_at, user_id;
+
+-- name: RestoreFeedToken :exec
+INSERT INTO feed_tokens (user_id, created_at, updated_at, token)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT DO NOTHING;
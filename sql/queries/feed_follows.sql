@@ -23,17 +23,39 @@ JOIN feeds ON inserted_feed_follow.feed_id = feeds.id;
 SELECT 
     feed_follows.*,
     users.name AS user_name,
-    feeds.name AS feed_name
+    feeds.name AS feed_name,
+    feeds.url AS feed_url,
+    feeds.site_url AS feed_site_url,
+    folders.name AS folder_name
 FROM feed_follows
 JOIN users ON feed_follows.user_id = users.id
 JOIN feeds ON feed_follows.feed_id = feeds.id
-WHERE users.name = $1;
+LEFT JOIN folders ON feed_follows.folder_id = folders.id
+WHERE users.name = $1
+ORDER BY folders.name NULLS FIRST, feeds.name;
 
 -- name: DeleteFeedFollow :exec
 DELETE FROM feed_follows
 WHERE feed_follows.user_id = $1
 AND feed_follows.feed_id = (
-    SELECT feed_id
+    SELECT id
     FROM feeds
     WHERE url = $2
-);
\ No newline at end of file
+);
+
+-- name: DeleteFeedFollowByFeed :execrows
+DELETE FROM feed_follows
+WHERE user_id = $1
+AND feed_id = $2;
+
+-- name: SetFeedFollowFolder :execrows
+UPDATE feed_follows
+SET folder_id = $3, updated_at = NOW()
+WHERE user_id = $1
+AND feed_id = $2;
+
+-- name: GetFeedFollow :one
+SELECT *
+FROM feed_follows
+WHERE user_id = $1
+AND feed_id = $2;
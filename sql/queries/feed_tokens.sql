@@ -0,0 +1,18 @@
+-- name: SetFeedToken :exec
+INSERT INTO feed_tokens (user_id, created_at, updated_at, token)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (user_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at, token = EXCLUDED.token;
+
+-- name: GetFeedToken :one
+SELECT * FROM feed_tokens
+WHERE user_id = $1;
+
+-- name: DeleteFeedToken :execrows
+DELETE FROM feed_tokens
+WHERE user_id = $1;
@@ -19,11 +19,26 @@ SELECT *
 FROM feeds
 WHERE url = $1;
 
+-- name: GetFeedByID :one
+SELECT *
+FROM feeds
+WHERE id = $1;
+
 -- name: MarkFeedFetched :exec
 UPDATE feeds
 SET updated_at = NOW(), last_fetched_at = NOW()
 WHERE id = $1;
 
+-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET updated_at = NOW(), site_url = $2
+WHERE id = $1;
+
+-- name: SetFeedFullText :exec
+UPDATE feeds
+SET updated_at = NOW(), full_text = $2
+WHERE id = $1;
+
 -- name: GenNextFeedToFetch :one
 SELECT *
 FROM feeds
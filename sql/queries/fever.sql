@@ -0,0 +1,117 @@
+-- name: SetFeverKey :exec
+INSERT INTO fever_keys (user_id, created_at, updated_at, api_key)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (user_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at, api_key = EXCLUDED.api_key;
+
+-- name: DeleteFeverKey :execrows
+DELETE FROM fever_keys
+WHERE user_id = $1;
+
+-- name: GetFeverKey :one
+SELECT * FROM fever_keys
+WHERE user_id = $1;
+
+-- name: GetUserByFeverKey :one
+SELECT users.*
+FROM users
+JOIN fever_keys
+ON users.id = fever_keys.user_id
+WHERE fever_keys.api_key = $1;
+
+-- name: GetFeverItems :many
+SELECT
+    posts.*,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    ) AS starred
+FROM posts
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg('user_id')
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = sqlc.arg('user_id')
+    AND post_hides.post_id = posts.id
+)
+AND (sqlc.narg('since_id')::bigint IS NULL OR posts.seq > sqlc.narg('since_id'))
+AND (sqlc.narg('max_id')::bigint IS NULL OR posts.seq < sqlc.narg('max_id'))
+AND (sqlc.narg('with_ids')::bigint[] IS NULL OR posts.seq = ANY(sqlc.narg('with_ids')::bigint[]))
+ORDER BY CASE WHEN sqlc.narg('since_id')::bigint IS NULL THEN -posts.seq ELSE posts.seq END
+LIMIT sqlc.arg('limit');
+
+-- name: CountFeverItems :one
+SELECT COUNT(*)
+FROM posts
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = $1
+    AND post_hides.post_id = posts.id
+);
+
+-- name: GetUnreadPostSeqs :many
+SELECT posts.seq
+FROM posts
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = $1
+    AND post_hides.post_id = posts.id
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_reads
+    WHERE post_reads.user_id = $1
+    AND post_reads.post_id = posts.id
+)
+ORDER BY posts.seq;
+
+-- name: GetStarredPostSeqs :many
+SELECT posts.seq
+FROM posts
+JOIN post_stars
+ON posts.id = post_stars.post_id
+WHERE post_stars.user_id = $1
+ORDER BY posts.seq;
+
+-- name: GetPostBySeq :one
+SELECT * FROM posts
+WHERE seq = $1;
+
+-- name: MarkFeedsReadBefore :exec
+INSERT INTO post_reads (created_at, user_id, post_id)
+SELECT sqlc.arg('created_at')::timestamp, feed_follows.user_id, posts.id
+FROM posts
+JOIN feeds
+ON posts.feed_id = feeds.id
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = sqlc.arg('user_id')
+AND (sqlc.narg('feed_seq')::bigint IS NULL OR feeds.seq = sqlc.narg('feed_seq'))
+AND (sqlc.narg('folder_seq')::bigint IS NULL OR folders.seq = sqlc.narg('folder_seq'))
+AND posts.created_at < sqlc.arg('before')
+ON CONFLICT DO NOTHING;
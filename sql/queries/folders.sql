@@ -0,0 +1,31 @@
+-- name: CreateFolder :one
+INSERT INTO folders (id, created_at, updated_at, name, user_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetFolder :one
+SELECT *
+FROM folders
+WHERE user_id = $1
+AND name = $2;
+
+-- name: GetFoldersForUser :many
+SELECT *
+FROM folders
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: RenameFolder :exec
+UPDATE folders
+SET name = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = $1;
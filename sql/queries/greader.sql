@@ -0,0 +1,99 @@
+-- name: GetGReaderItems :many
+SELECT
+    posts.*,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    ) AS starred,
+    feeds.name AS feed_name,
+    feeds.url AS feed_url,
+    feeds.site_url AS feed_site_url,
+    folders.name AS folder_name
+FROM posts
+JOIN feeds
+ON posts.feed_id = feeds.id
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = sqlc.arg('user_id')
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = sqlc.arg('user_id')
+    AND post_hides.post_id = posts.id
+)
+AND (sqlc.narg('feed')::text IS NULL OR feeds.url = sqlc.narg('feed'))
+AND (sqlc.narg('folder')::text IS NULL OR folders.name = sqlc.narg('folder'))
+AND (
+    NOT sqlc.arg('starred_only')::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    )
+)
+AND (
+    NOT sqlc.arg('unread_only')::boolean
+    OR NOT EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (
+    NOT sqlc.arg('read_only')::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (sqlc.narg('newer_than')::timestamp IS NULL OR posts.created_at > sqlc.narg('newer_than'))
+AND (sqlc.narg('older_than')::timestamp IS NULL OR posts.created_at < sqlc.narg('older_than'))
+AND (sqlc.narg('with_ids')::bigint[] IS NULL OR posts.seq = ANY(sqlc.narg('with_ids')::bigint[]))
+AND (
+    sqlc.narg('continuation')::bigint IS NULL
+    OR CASE WHEN sqlc.arg('oldest_first')::boolean THEN posts.seq > sqlc.narg('continuation') ELSE posts.seq < sqlc.narg('continuation') END
+)
+ORDER BY CASE WHEN sqlc.arg('oldest_first')::boolean THEN posts.seq ELSE -posts.seq END
+LIMIT sqlc.arg('limit');
+
+-- name: GetUnreadCounts :many
+SELECT
+    feeds.url AS feed_url,
+    folders.name AS folder_name,
+    COUNT(*) AS count,
+    MAX(posts.created_at)::timestamp AS newest
+FROM posts
+JOIN feeds
+ON posts.feed_id = feeds.id
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = $1
+    AND post_hides.post_id = posts.id
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_reads
+    WHERE post_reads.user_id = $1
+    AND post_reads.post_id = posts.id
+)
+GROUP BY feeds.url, folders.name;
@@ -0,0 +1,20 @@
+-- name: SavePostArchive :one
+INSERT INTO post_archives (id, created_at, updated_at, user_id, post_id, format, path)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at, format = EXCLUDED.format, path = EXCLUDED.path
+RETURNING *;
+
+-- name: GetPostArchive :one
+SELECT *
+FROM post_archives
+WHERE user_id = $1
+AND post_id = $2;
@@ -0,0 +1,42 @@
+-- name: MarkPostRead :exec
+INSERT INTO post_reads (created_at, user_id, post_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: MarkPostUnread :exec
+DELETE FROM post_reads
+WHERE user_id = $1
+AND post_id = $2;
+
+-- name: StarPost :exec
+INSERT INTO post_stars (created_at, user_id, post_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnstarPost :exec
+DELETE FROM post_stars
+WHERE user_id = $1
+AND post_id = $2;
+
+-- name: GetPostState :one
+SELECT
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = $1
+        AND post_reads.post_id = $2
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = $1
+        AND post_stars.post_id = $2
+    ) AS starred;
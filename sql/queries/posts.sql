@@ -13,15 +13,221 @@ VALUES (
 RETURNING *;
 
 -- name: GetPostsForUser :many
-SELECT posts.*
+SELECT
+    posts.*,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    ) AS starred
 FROM posts
 JOIN feed_follows
 ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.user_id = $1
-ORDER BY published_at DESC
-LIMIT $2;
+JOIN feeds
+ON posts.feed_id = feeds.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = sqlc.arg('user_id')
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = sqlc.arg('user_id')
+    AND post_hides.post_id = posts.id
+)
+AND (sqlc.narg('feed')::text IS NULL OR feeds.url = sqlc.narg('feed') OR feeds.name = sqlc.narg('feed'))
+AND (sqlc.narg('folder')::text IS NULL OR folders.name = sqlc.narg('folder'))
+AND (
+    sqlc.narg('tag')::text IS NULL
+    OR posts.id IN (
+        SELECT post_tags.post_id
+        FROM post_tags
+        JOIN tags
+        ON post_tags.tag_id = tags.id
+        WHERE tags.user_id = sqlc.arg('user_id')
+        AND tags.name = sqlc.narg('tag')
+    )
+)
+AND (sqlc.narg('from')::timestamp IS NULL OR CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END >= sqlc.narg('from'))
+AND (sqlc.narg('to')::timestamp IS NULL OR CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END < sqlc.narg('to'))
+AND (
+    sqlc.narg('search')::text IS NULL
+    OR posts.title ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.description ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.content ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+)
+AND (
+    NOT sqlc.arg('unread_only')::boolean
+    OR NOT EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (
+    NOT sqlc.arg('starred_only')::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    )
+)
+AND (
+    sqlc.narg('before')::uuid IS NULL
+    OR (CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END, posts.id) < (
+        SELECT CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN c.created_at ELSE c.published_at END, c.id
+        FROM posts c
+        WHERE c.id = sqlc.narg('before')
+    )
+)
+ORDER BY CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END DESC, posts.id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: GetPostsForUserAfter :many
+SELECT
+    posts.*,
+    EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    ) AS read,
+    EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    ) AS starred
+FROM posts
+JOIN feed_follows
+ON posts.feed_id = feed_follows.feed_id
+JOIN feeds
+ON posts.feed_id = feeds.id
+LEFT JOIN folders
+ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = sqlc.arg('user_id')
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_hides
+    WHERE post_hides.user_id = sqlc.arg('user_id')
+    AND post_hides.post_id = posts.id
+)
+AND (sqlc.narg('feed')::text IS NULL OR feeds.url = sqlc.narg('feed') OR feeds.name = sqlc.narg('feed'))
+AND (sqlc.narg('folder')::text IS NULL OR folders.name = sqlc.narg('folder'))
+AND (
+    sqlc.narg('tag')::text IS NULL
+    OR posts.id IN (
+        SELECT post_tags.post_id
+        FROM post_tags
+        JOIN tags
+        ON post_tags.tag_id = tags.id
+        WHERE tags.user_id = sqlc.arg('user_id')
+        AND tags.name = sqlc.narg('tag')
+    )
+)
+AND (sqlc.narg('from')::timestamp IS NULL OR CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END >= sqlc.narg('from'))
+AND (sqlc.narg('to')::timestamp IS NULL OR CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END < sqlc.narg('to'))
+AND (
+    sqlc.narg('search')::text IS NULL
+    OR posts.title ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.description ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    OR posts.content ILIKE '%' || replace(replace(replace(sqlc.narg('search'), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+)
+AND (
+    NOT sqlc.arg('unread_only')::boolean
+    OR NOT EXISTS (
+        SELECT 1
+        FROM post_reads
+        WHERE post_reads.user_id = sqlc.arg('user_id')
+        AND post_reads.post_id = posts.id
+    )
+)
+AND (
+    NOT sqlc.arg('starred_only')::boolean
+    OR EXISTS (
+        SELECT 1
+        FROM post_stars
+        WHERE post_stars.user_id = sqlc.arg('user_id')
+        AND post_stars.post_id = posts.id
+    )
+)
+AND (CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END, posts.id) > (
+    SELECT CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN c.created_at ELSE c.published_at END, c.id
+    FROM posts c
+    WHERE c.id = sqlc.arg('after')
+)
+ORDER BY CASE WHEN sqlc.arg('sort')::text = 'fetched' THEN posts.created_at ELSE posts.published_at END ASC, posts.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: SetPostContent :exec
+UPDATE posts
+SET updated_at = NOW(), content = $2
+WHERE id = $1;
+
+-- name: GetPost :one
+SELECT *
+FROM posts
+WHERE id = $1;
 
 -- name: GetPosts :many
 SELECT *
 FROM posts
-LIMIT $1;
\ No newline at end of file
+LIMIT $1;
+
+-- name: SearchPosts :many
+SELECT
+    matches.id,
+    matches.title,
+    matches.url,
+    matches.published_at,
+    matches.feed_name,
+    matches.rank,
+    ts_headline(
+        'english',
+        matches.title || ' ' || regexp_replace(matches.description, '<[^>]*>', ' ', 'g'),
+        websearch_to_tsquery('english', sqlc.arg('query')::text),
+        'StartSel=**, StopSel=**, MaxWords=35, MinWords=15, MaxFragments=2'
+    ) AS snippet
+FROM (
+    SELECT
+        posts.id,
+        posts.title,
+        posts.url,
+        posts.published_at,
+        posts.description,
+        feeds.name AS feed_name,
+        ts_rank(posts.search_vector, websearch_to_tsquery('english', sqlc.arg('query')::text)) AS rank
+    FROM posts
+    JOIN feeds
+    ON posts.feed_id = feeds.id
+    WHERE posts.search_vector @@ websearch_to_tsquery('english', sqlc.arg('query')::text)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM post_hides
+        WHERE post_hides.user_id = sqlc.arg('user_id')
+        AND post_hides.post_id = posts.id
+    )
+    AND (
+        sqlc.arg('all_feeds')::boolean
+        OR posts.feed_id IN (
+            SELECT feed_follows.feed_id
+            FROM feed_follows
+            LEFT JOIN folders
+            ON feed_follows.folder_id = folders.id
+            WHERE feed_follows.user_id = sqlc.arg('user_id')
+            AND (sqlc.narg('folder')::text IS NULL OR folders.name = sqlc.narg('folder'))
+        )
+    )
+    ORDER BY rank DESC, posts.published_at DESC, posts.id DESC
+    LIMIT sqlc.arg('limit')
+) AS matches
+ORDER BY matches.rank DESC, matches.published_at DESC, matches.id DESC;
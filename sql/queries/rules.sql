@@ -0,0 +1,63 @@
+-- name: CreateRule :one
+INSERT INTO rules (id, created_at, updated_at, user_id, feed_id, title_regex, content_regex, action)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+RETURNING *;
+
+-- name: GetRule :one
+SELECT * FROM rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetRulesForUser :many
+SELECT rules.*, feeds.url AS feed_url
+FROM rules
+LEFT JOIN feeds
+ON rules.feed_id = feeds.id
+WHERE rules.user_id = $1
+ORDER BY rules.created_at;
+
+-- name: GetRulesForFeed :many
+SELECT * FROM rules
+WHERE feed_id = $1
+OR (
+    feed_id IS NULL
+    AND EXISTS (
+        SELECT 1
+        FROM feed_follows
+        WHERE feed_follows.user_id = rules.user_id
+        AND feed_follows.feed_id = $1
+    )
+)
+ORDER BY created_at;
+
+-- name: DeleteRule :execrows
+DELETE FROM rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: HidePost :exec
+INSERT INTO post_hides (created_at, user_id, post_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnhidePost :execrows
+DELETE FROM post_hides
+WHERE user_id = $1
+AND post_id = $2;
+
+-- name: GetHiddenPostIDs :many
+SELECT post_id
+FROM post_hides
+WHERE user_id = sqlc.arg('user_id')
+AND post_id = ANY(sqlc.arg('post_ids')::uuid[]);
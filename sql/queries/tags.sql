@@ -0,0 +1,57 @@
+-- name: CreateTag :one
+INSERT INTO tags (id, created_at, updated_at, name, user_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (user_id, name) DO UPDATE
+SET updated_at = tags.updated_at
+RETURNING *;
+
+-- name: AddPostTag :exec
+INSERT INTO post_tags (created_at, post_id, tag_id)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: RemovePostTag :execrows
+DELETE FROM post_tags
+WHERE post_tags.post_id = $1
+AND post_tags.tag_id = (
+    SELECT tags.id
+    FROM tags
+    WHERE tags.user_id = $2
+    AND tags.name = $3
+);
+
+-- name: DeleteUnusedTags :exec
+DELETE FROM tags
+WHERE user_id = $1
+AND NOT EXISTS (
+    SELECT 1
+    FROM post_tags
+    WHERE post_tags.tag_id = tags.id
+);
+
+-- name: GetTagsForPost :many
+SELECT tags.name
+FROM tags
+JOIN post_tags ON tags.id = post_tags.tag_id
+WHERE post_tags.post_id = $1
+AND tags.user_id = $2
+ORDER BY tags.name;
+
+-- name: GetTagsForUser :many
+SELECT tags.name, COUNT(post_tags.post_id) AS post_count
+FROM tags
+LEFT JOIN post_tags ON tags.id = post_tags.tag_id
+WHERE tags.user_id = sqlc.arg('user_id')
+AND tags.name LIKE replace(replace(replace(sqlc.arg('prefix')::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+GROUP BY tags.id, tags.name
+ORDER BY tags.name;
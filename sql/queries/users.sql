@@ -23,4 +23,9 @@ DELETE FROM users;
 
 -- name: GetUsers :many
 SELECT *
-FROM users;
\ No newline at end of file
+FROM users;
+
+-- name: SetUserEmail :exec
+UPDATE users
+SET email = $2, updated_at = $3
+WHERE id = $1;
@@ -0,0 +1,89 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (id, created_at, updated_at, user_id, feed_id, url, secret)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetWebhooksForUser :many
+SELECT
+    webhooks.*,
+    feeds.url AS feed_url,
+    COALESCE((
+        SELECT webhook_deliveries.status_code
+        FROM webhook_deliveries
+        WHERE webhook_deliveries.webhook_id = webhooks.id
+        AND webhook_deliveries.attempts > 0
+        ORDER BY webhook_deliveries.updated_at DESC
+        LIMIT 1
+    ), 0)::integer AS last_status_code
+FROM webhooks
+LEFT JOIN feeds
+ON webhooks.feed_id = feeds.id
+WHERE webhooks.user_id = $1
+ORDER BY webhooks.created_at;
+
+-- name: GetWebhooksForFeed :many
+SELECT * FROM webhooks
+WHERE feed_id = $1
+OR (
+    feed_id IS NULL
+    AND EXISTS (
+        SELECT 1
+        FROM feed_follows
+        WHERE feed_follows.user_id = webhooks.user_id
+        AND feed_follows.feed_id = $1
+    )
+);
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (id, created_at, updated_at, webhook_id, event, payload, next_attempt_at)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetDueWebhookDeliveries :many
+SELECT webhook_deliveries.*, webhooks.url, webhooks.secret
+FROM webhook_deliveries
+JOIN webhooks
+ON webhook_deliveries.webhook_id = webhooks.id
+WHERE webhook_deliveries.next_attempt_at <= sqlc.arg('now')::timestamp
+ORDER BY webhook_deliveries.next_attempt_at
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET updated_at = $2,
+    attempts = $3,
+    status_code = $4,
+    last_error = $5,
+    next_attempt_at = $6,
+    delivered_at = $7
+WHERE id = $1;
+
+-- name: GetWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
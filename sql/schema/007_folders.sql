@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE folders(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+name TEXT NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+UNIQUE (user_id, name)
+);
+
+ALTER TABLE feed_follows
+ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feed_follows
+DROP COLUMN folder_id;
+
+DROP TABLE folders;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE tags(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+name TEXT NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+UNIQUE (user_id, name)
+);
+
+CREATE TABLE post_tags(
+created_at TIMESTAMP NOT NULL,
+post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+PRIMARY KEY (post_id, tag_id)
+);
+
+-- +goose Down
+DROP TABLE post_tags;
+
+DROP TABLE tags;
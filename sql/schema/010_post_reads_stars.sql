@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE post_reads(
+created_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+PRIMARY KEY (user_id, post_id)
+);
+
+CREATE TABLE post_stars(
+created_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+PRIMARY KEY (user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE post_stars;
+
+DROP TABLE post_reads;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN seq BIGSERIAL UNIQUE;
+
+ALTER TABLE folders
+ADD COLUMN seq BIGSERIAL UNIQUE;
+
+ALTER TABLE posts
+ADD COLUMN seq BIGSERIAL UNIQUE;
+
+CREATE TABLE fever_keys(
+user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+api_key TEXT UNIQUE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE fever_keys;
+
+ALTER TABLE posts
+DROP COLUMN seq;
+
+ALTER TABLE folders
+DROP COLUMN seq;
+
+ALTER TABLE feeds
+DROP COLUMN seq;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE feed_tokens(
+user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+token TEXT UNIQUE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE feed_tokens;
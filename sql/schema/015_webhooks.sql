@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE webhooks(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+url TEXT NOT NULL,
+secret TEXT NOT NULL
+);
+
+CREATE TABLE webhook_deliveries(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+event TEXT NOT NULL,
+payload TEXT NOT NULL,
+attempts INTEGER NOT NULL DEFAULT 0,
+status_code INTEGER,
+last_error TEXT NOT NULL DEFAULT '',
+next_attempt_at TIMESTAMP,
+delivered_at TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries(next_attempt_at);
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE rules(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+title_regex TEXT NOT NULL DEFAULT '',
+content_regex TEXT NOT NULL DEFAULT '',
+action TEXT NOT NULL
+);
+
+CREATE TABLE post_hides(
+created_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+PRIMARY KEY (user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE post_hides;
+
+DROP TABLE rules;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE watch_words(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL,
+user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+word TEXT NOT NULL,
+regex BOOLEAN NOT NULL DEFAULT false,
+notify_webhooks BOOLEAN NOT NULL DEFAULT false,
+notify_email BOOLEAN NOT NULL DEFAULT false,
+UNIQUE (user_id, word)
+);
+
+CREATE TABLE alerts(
+id UUID PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+watch_word_id UUID NOT NULL REFERENCES watch_words(id) ON DELETE CASCADE,
+post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+snippet TEXT NOT NULL,
+UNIQUE (watch_word_id, post_id)
+);
+
+CREATE INDEX alerts_created_at_idx ON alerts(created_at);
+
+-- +goose Down
+DROP TABLE alerts;
+
+DROP TABLE watch_words;